@@ -0,0 +1,118 @@
+package fury
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/nandaryanizar/fury/model"
+)
+
+// FindInBatches method scan queried records to dest in batches of batchSize and call the callback after each batch
+//	Dest must be pointer to slice of pointer to struct with primary_key tagged field, the records are iterated by the primary key
+//	using keyset condition instead of offset, so OrderBy, Limit and Offset query option should not be used.
+//	Callback must be function with dest type as its only parameter and return error, e.g. func(*[]*Account) error.
+//	Returning error from callback stop the iteration.
+func (db *DB) FindInBatches(dest interface{}, batchSize int, callback interface{}, opts ...QueryOption) error {
+	if batchSize < 1 {
+		return errors.New("Error: batch size must be greater than zero")
+	}
+
+	fn := reflect.ValueOf(callback)
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+	if fn.Kind() != reflect.Func || fn.Type().NumIn() != 1 || fn.Type().NumOut() != 1 ||
+		fn.Type().In(0) != reflect.TypeOf(dest) || fn.Type().Out(0) != errorType {
+		return errors.New("Error: callback must be function with the dest type as parameter and error as return value")
+	}
+
+	destVal := reflect.ValueOf(dest)
+	if destVal.Kind() != reflect.Ptr || destVal.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("Error: expected pointer to slice, found %v", destVal.Kind())
+	}
+	sliceVal := destVal.Elem()
+
+	q, err := NewQuery(reflect.New(sliceVal.Type()).Interface())
+	if err != nil {
+		return err
+	}
+
+	pkColumns := []string{}
+	for _, f := range q.modelPtr.PrimaryKeys {
+		pkColumns = append(pkColumns, f.ColumnName())
+	}
+
+	if len(pkColumns) == 0 {
+		return fmt.Errorf("Error: model %s has no primary key", q.modelPtr.Name)
+	}
+
+	var lastValues []interface{}
+	for {
+		sliceVal.Set(reflect.MakeSlice(sliceVal.Type(), 0, batchSize))
+
+		batchOpts := append([]QueryOption{}, opts...)
+		if lastValues != nil {
+			batchOpts = append(batchOpts, Where(keysetCondition(pkColumns, lastValues)))
+		}
+		batchOpts = append(batchOpts, OrderBy(stringsToInterfaces(pkColumns)...), Limit(batchSize))
+
+		if err := db.Find(dest, batchOpts...); err != nil {
+			return err
+		}
+
+		if sliceVal.Len() == 0 {
+			return nil
+		}
+
+		ret := fn.Call([]reflect.Value{destVal})
+		if err, ok := ret[0].Interface().(error); ok && err != nil {
+			return err
+		}
+
+		if sliceVal.Len() < batchSize {
+			return nil
+		}
+
+		lastValues, err = primaryKeyValues(sliceVal.Index(sliceVal.Len() - 1).Interface())
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Create condition which match records after the specified primary key values
+func keysetCondition(columns []string, values []interface{}) *Expression {
+	if len(columns) == 1 {
+		return IsGreaterThan(columns[0], values[0])
+	}
+
+	cols := []interface{}{}
+	for _, col := range columns {
+		cols = append(cols, Col(col))
+	}
+
+	return IsGreaterThan(Tuple(cols...), Tuple(values...))
+}
+
+// Get primary key values of pointer to struct, ordered as the fields are declared
+func primaryKeyValues(modelInterface interface{}) ([]interface{}, error) {
+	_, m, err := model.NewModels(modelInterface)
+	if err != nil {
+		return nil, err
+	}
+
+	values := []interface{}{}
+	for _, f := range m.PrimaryKeys {
+		values = append(values, model.BindValue(f.Value))
+	}
+
+	return values, nil
+}
+
+func stringsToInterfaces(strs []string) []interface{} {
+	out := []interface{}{}
+	for _, str := range strs {
+		out = append(out, str)
+	}
+
+	return out
+}
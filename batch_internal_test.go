@@ -0,0 +1,64 @@
+package fury
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeysetCondition(t *testing.T) {
+	cases := []struct {
+		columns  []string
+		values   []interface{}
+		want     string
+		wantArgs []interface{}
+	}{
+		{[]string{"userid"}, []interface{}{10}, "userid > ?", []interface{}{10}},
+		{[]string{"userid", "counter"}, []interface{}{10, 2}, "(userid, counter) > (?, ?)", []interface{}{10, 2}},
+	}
+
+	for _, tc := range cases {
+		have, args, err := keysetCondition(tc.columns, tc.values).ToString()
+		if err != nil {
+			t.Error(err)
+		}
+
+		if have != tc.want || !reflect.DeepEqual(args, tc.wantArgs) {
+			t.Errorf("Error: expected %v and %v, found %v and %v", tc.want, tc.wantArgs, have, args)
+		}
+	}
+}
+
+func TestPrimaryKeyValues(t *testing.T) {
+	have, err := primaryKeyValues(&User{UserID: 5, Counter: 3})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if want := []interface{}{5}; !reflect.DeepEqual(want, have) {
+		t.Errorf("Error: expected %v, found %v", want, have)
+	}
+}
+
+func TestFindInBatchesInvalidArguments(t *testing.T) {
+	type noPK struct {
+		Name string
+	}
+
+	cases := []struct {
+		dest      interface{}
+		batchSize int
+		callback  interface{}
+	}{
+		{&[]*User{}, 0, func(*[]*User) error { return nil }},
+		{&[]*User{}, 10, func([]*User) error { return nil }},
+		{&User{}, 10, func(*User) error { return nil }},
+		{&[]*noPK{}, 10, func(*[]*noPK) error { return nil }},
+	}
+
+	db := &DB{}
+	for _, tc := range cases {
+		if err := db.FindInBatches(tc.dest, tc.batchSize, tc.callback); err == nil {
+			t.Error("Expected error found nil")
+		}
+	}
+}
@@ -22,6 +22,11 @@ type Configuration struct {
 	ConnMaxLifetime time.Duration
 	MaxIdleConns    int
 	MaxOpenConns    int
+
+	// Clock, time zone and precision of the time set automatically to created_at, updated_at and soft_delete field
+	Clock         func() time.Time
+	TimeZone      *time.Location
+	TimePrecision time.Duration
 }
 
 // LoadConfiguration load environment variable and create new configuration struct based on the variable
@@ -55,6 +60,11 @@ func LoadConfiguration(fileName string) (*Configuration, error) {
 		return nil, err
 	}
 
+	timeZone, err := getEnvAsLocation("DATABASE_TIMEZONE", nil)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Configuration{
 		Username:        username,
 		Password:        password,
@@ -66,9 +76,30 @@ func LoadConfiguration(fileName string) (*Configuration, error) {
 		MaxIdleConns:    getEnvAsInt("DATABASE_MAXIDLECONNS", 2),
 		MaxOpenConns:    getEnvAsInt("DATABASE_MAXOPENCONNS", 0),
 		ConnMaxLifetime: getEnvAsTimeDuration("DATABASE_CONNMAXXLIFETIME", 0),
+		TimeZone:        timeZone,
+		TimePrecision:   getEnvAsTimeDuration("DATABASE_TIMEPRECISION", time.Microsecond),
 	}, nil
 }
 
+// Now return current time of the clock, converted to the time zone and truncated to the precision
+// 	Clock default to time.Now, and time zone default to the clock time zone
+func (c *Configuration) Now() time.Time {
+	now := time.Now()
+	if c.Clock != nil {
+		now = c.Clock()
+	}
+
+	if c.TimeZone != nil {
+		now = now.In(c.TimeZone)
+	}
+
+	if c.TimePrecision > 0 {
+		now = now.Truncate(c.TimePrecision)
+	}
+
+	return now
+}
+
 // Lookup env variable and return default value if not exists
 func getEnv(key string, defaultVal string) string {
 	if value, exists := os.LookupEnv(key); exists {
@@ -105,6 +136,18 @@ func getEnvAsTimeDuration(key string, defaultVal time.Duration) time.Duration {
 	return defaultVal
 }
 
+// Lookup env variable and return default value as time zone location if not exists
+func getEnvAsLocation(key string, defaultVal *time.Location) (*time.Location, error) {
+	if value, exists := os.LookupEnv(key); exists {
+		loc, err := time.LoadLocation(value)
+		if err != nil {
+			return nil, fmt.Errorf("Error: invalid time zone %s", value)
+		}
+		return loc, nil
+	}
+	return defaultVal, nil
+}
+
 // Lookup env variable and return default value as bool if not exists
 func getEnvAsBool(key string, defaultVal bool) bool {
 	if value, exists := os.LookupEnv(key); exists {
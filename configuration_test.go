@@ -3,6 +3,7 @@ package fury_test
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/nandaryanizar/fury"
 )
@@ -25,6 +26,7 @@ func TestInitializeDefault(t *testing.T) {
 				ConnMaxLifetime: 0,
 				MaxOpenConns:    0,
 				MaxIdleConns:    2,
+				TimePrecision:   time.Microsecond,
 			},
 		},
 	}
@@ -40,3 +42,24 @@ func TestInitializeDefault(t *testing.T) {
 		}
 	}
 }
+
+func TestConfigurationNow(t *testing.T) {
+	fixed := time.Date(2019, 6, 28, 2, 26, 0, 123456789, time.UTC)
+	jakarta := time.FixedZone("WIB", 7*60*60)
+
+	cases := []struct {
+		have *fury.Configuration
+		want time.Time
+	}{
+		{&fury.Configuration{Clock: func() time.Time { return fixed }}, fixed},
+		{&fury.Configuration{Clock: func() time.Time { return fixed }, TimePrecision: time.Millisecond}, fixed.Truncate(time.Millisecond)},
+		{&fury.Configuration{Clock: func() time.Time { return fixed }, TimeZone: jakarta}, fixed.In(jakarta)},
+	}
+
+	for _, tc := range cases {
+		have := tc.have.Now()
+		if !have.Equal(tc.want) || have.Location() != tc.want.Location() || have.Nanosecond() != tc.want.Nanosecond() {
+			t.Errorf("Error: expected %v, found %v", tc.want, have)
+		}
+	}
+}
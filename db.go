@@ -1,15 +1,23 @@
 package fury
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
 	"time"
 )
 
+// ErrStaleObject is returned by Update when versioned record has been modified or deleted since it was loaded
+var ErrStaleObject = errors.New("Error: stale object, record has been modified or deleted")
+
 // DB object consists of DB connection pool and configuration
 // 	Use Connect(config *Configuration) to create new instance of this struct.
 type DB struct {
 	ConnectionPooler
 	config *Configuration
 	query  *Query
+	tx     *sql.Tx
 }
 
 // Connect to database, instantiate DB struct for querying to database.
@@ -55,11 +63,53 @@ func (db *DB) clone(model interface{}) (*DB, error) {
 		return nil, err
 	}
 
+	return db.withQuery(q), nil
+}
+
+// Create new DB with specified query context
+func (db *DB) withQuery(q *Query) *DB {
+	q.clock = db.config.Now
 	return &DB{
 		ConnectionPooler: db.ConnectionPooler,
 		config:           db.config,
 		query:            q,
-	}, nil
+		tx:               db.tx,
+	}
+}
+
+// Clone DB and apply query options to the new query context
+func (db *DB) cloneWithOptions(model interface{}, opts ...QueryOption) (*DB, error) {
+	newDB, err := db.clone(model)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := newDB.applyOptions(opts...); err != nil {
+		return nil, err
+	}
+
+	return newDB, nil
+}
+
+// SetClock method replace the clock used to set created_at, updated_at and soft_delete field, e.g. fixed time for testing
+//	The configuration is copied, so the clock only apply to this DB and the transactions started from it afterwards.
+//	It is not safe for concurrent use, call it before the DB is used by other goroutines.
+func (db *DB) SetClock(clock func() time.Time) {
+	config := *db.config
+	config.Clock = clock
+	db.config = &config
+}
+
+// Apply query options to the query context
+func (db *DB) applyOptions(opts ...QueryOption) error {
+	for _, opt := range opts {
+		_, err := opt(db.query)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // First method return first record ordered by primary key
@@ -70,87 +120,275 @@ func (db *DB) First(model interface{}, opts ...QueryOption) error {
 
 // Find method return all record queried with specified conditions
 func (db *DB) Find(model interface{}, opts ...QueryOption) error {
-	newDB, err := db.clone(model)
+	newDB, err := db.cloneWithOptions(model, opts...)
 	if err != nil {
 		return err
 	}
 
-	for _, opt := range opts {
-		_, err := opt(newDB.query)
-		if err != nil {
-			return err
-		}
-	}
-
 	return newDB.executeSelectQuery()
 }
 
 // Insert query method
 func (db *DB) Insert(model interface{}, opts ...QueryOption) error {
-	newDB, err := db.clone(model)
+	newDB, err := db.cloneWithOptions(model, opts...)
 	if err != nil {
 		return err
 	}
 
-	for _, opt := range opts {
-		_, err := opt(newDB.query)
-		if err != nil {
-			return err
-		}
-	}
-
 	return newDB.executeInsertQuery()
 }
 
 // Update query method
 func (db *DB) Update(model interface{}, opts ...QueryOption) error {
-	newDB, err := db.clone(model)
+	newDB, err := db.cloneWithOptions(model, opts...)
 	if err != nil {
 		return err
 	}
 
-	for _, opt := range opts {
-		_, err := opt(newDB.query)
-		if err != nil {
-			return err
-		}
+	return newDB.executeUpdateQuery()
+}
+
+// UpdateMap method update the columns in values map of the record(s) specified by model primary key and query options
+func (db *DB) UpdateMap(model interface{}, values map[string]interface{}, opts ...QueryOption) error {
+	if len(values) < 1 {
+		return errors.New("Error: update values cannot be empty")
+	}
+
+	newDB, err := db.cloneWithOptions(model, opts...)
+	if err != nil {
+		return err
 	}
 
+	newDB.query.updateValues = values
 	return newDB.executeUpdateQuery()
 }
 
+// UpdateWhere method update all records of the model table matching the query options in single query without loading the models
+//	It return the number of affected rows, and require filter unless AllowFullTable option is used
+func (db *DB) UpdateWhere(model interface{}, values Set, opts ...QueryOption) (int64, error) {
+	if len(values) < 1 {
+		return 0, errors.New("Error: update values cannot be empty")
+	}
+
+	newDB, err := db.cloneWithOptions(model, opts...)
+	if err != nil {
+		return 0, err
+	}
+
+	newDB.query.updateValues = values
+	if err := newDB.query.prepareUpdateQuery(); err != nil {
+		return 0, err
+	}
+
+	return newDB.executeSetBasedQuery()
+}
+
 // Delete query method
 func (db *DB) Delete(model interface{}, opts ...QueryOption) error {
-	newDB, err := db.clone(model)
+	newDB, err := db.cloneWithOptions(model, opts...)
 	if err != nil {
 		return err
 	}
 
-	for _, opt := range opts {
-		_, err := opt(newDB.query)
-		if err != nil {
-			return err
-		}
+	return newDB.executeDeleteQuery()
+}
+
+// DeleteWhere method delete all records of the model table matching the query options in single query without loading the models
+//	It return the number of affected rows, and require filter unless AllowFullTable option is used
+func (db *DB) DeleteWhere(model interface{}, opts ...QueryOption) (int64, error) {
+	newDB, err := db.cloneWithOptions(model, opts...)
+	if err != nil {
+		return 0, err
 	}
 
-	return newDB.executeDeleteQuery()
+	if err := newDB.query.prepareDeleteQuery(); err != nil {
+		return 0, err
+	}
+
+	return newDB.executeSetBasedQuery()
+}
+
+// Execute prepared set-based query, returned rows are scanned to the passed model(s)
+func (db *DB) executeSetBasedQuery() (int64, error) {
+	db.query.modelPtrCtr = -1
+
+	affected, err := db.execRowsAffected(true)
+	if err != nil {
+		return 0, err
+	}
+
+	db.setRowsAffected(affected)
+	return affected, nil
+}
+
+// Count method return number of record queried with specified conditions
+func (db *DB) Count(model interface{}, opts ...QueryOption) (int64, error) {
+	newDB, err := db.cloneWithOptions(model, opts...)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := newDB.checkLockInTransaction(); err != nil {
+		return 0, err
+	}
+
+	if err := newDB.query.prepareCountQuery(); err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err := newDB.QueryRow(newDB.query.SQL, newDB.query.args...).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Exists method return true if there is at least one record queried with specified conditions
+func (db *DB) Exists(model interface{}, opts ...QueryOption) (bool, error) {
+	newDB, err := db.cloneWithOptions(model, opts...)
+	if err != nil {
+		return false, err
+	}
+
+	if err := newDB.checkLockInTransaction(); err != nil {
+		return false, err
+	}
+
+	if err := newDB.query.prepareExistsQuery(); err != nil {
+		return false, err
+	}
+
+	var exists bool
+	if err := newDB.QueryRow(newDB.query.SQL, newDB.query.args...).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// Sum method return sum of the column values, return zero if there is no record
+func (db *DB) Sum(model interface{}, column string, opts ...QueryOption) (float64, error) {
+	var sum sql.NullFloat64
+	if err := db.aggregate(model, "SUM", column, &sum, opts...); err != nil {
+		return 0, err
+	}
+
+	return sum.Float64, nil
+}
+
+// Avg method return average of the column values, return zero if there is no record
+func (db *DB) Avg(model interface{}, column string, opts ...QueryOption) (float64, error) {
+	var avg sql.NullFloat64
+	if err := db.aggregate(model, "AVG", column, &avg, opts...); err != nil {
+		return 0, err
+	}
+
+	return avg.Float64, nil
+}
+
+// Min method scan minimum value of the column to dest
+// 	The value is NULL if there is no record, use dest that can scan NULL value (e.g. sql.NullInt64) for that case
+func (db *DB) Min(model interface{}, column string, dest interface{}, opts ...QueryOption) error {
+	return db.aggregate(model, "MIN", column, dest, opts...)
+}
+
+// Max method scan maximum value of the column to dest
+// 	The value is NULL if there is no record, use dest that can scan NULL value (e.g. sql.NullInt64) for that case
+func (db *DB) Max(model interface{}, column string, dest interface{}, opts ...QueryOption) error {
+	return db.aggregate(model, "MAX", column, dest, opts...)
+}
+
+func (db *DB) aggregate(model interface{}, function, column string, dest interface{}, opts ...QueryOption) error {
+	newDB, err := db.cloneWithOptions(model, opts...)
+	if err != nil {
+		return err
+	}
+
+	if err := newDB.checkLockInTransaction(); err != nil {
+		return err
+	}
+
+	if err := newDB.query.prepareAggregateQuery(function, column); err != nil {
+		return err
+	}
+
+	return newDB.QueryRow(newDB.query.SQL, newDB.query.args...).Scan(dest)
+}
+
+// Pluck method scan single column of all queried record to dest, dest must be pointer to slice
+//	As there is no model, the table must be specified with Table query option
+func (db *DB) Pluck(dest interface{}, column string, opts ...QueryOption) error {
+	q, err := newScalarQuery(dest, column)
+	if err != nil {
+		return err
+	}
+
+	newDB := db.withQuery(q)
+	if err := newDB.applyOptions(opts...); err != nil {
+		return err
+	}
+
+	return newDB.executeSelectQuery()
 }
 
 func (db *DB) executeSelectQuery() error {
+	if err := db.checkLockInTransaction(); err != nil {
+		return err
+	}
+
 	if err := db.query.prepareSelectQuery(); err != nil {
 		return err
 	}
 
+	return db.executeScanQuery()
+}
+
+// Row locking only last until the end of transaction, so using it outside transaction is a mistake
+func (db *DB) checkLockInTransaction() error {
+	if db.query.lock != nil && db.tx == nil {
+		return errors.New("Error: locking query option can only be used inside transaction")
+	}
+
+	return nil
+}
+
+// Run prepared query and scan the result to the query destination
+func (db *DB) executeScanQuery() error {
 	rows, err := db.Query(db.query.SQL, db.query.args...)
 	if err != nil {
 		return err
 	}
+	defer rows.Close()
 
 	columns, err := rows.Columns()
 	if err != nil {
 		return err
 	}
 
+	switch db.query.destination {
+	case mapDestination:
+		err = db.scanRowsToMaps(rows, columns)
+	case scalarDestination:
+		err = db.scanRowsToScalars(rows, columns)
+	default:
+		err = db.scanRowsToModels(rows, columns)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return rows.Err()
+}
+
+func (db *DB) scanRowsToModels(rows *sql.Rows, columns []string) error {
+	if db.query.strictColumns {
+		if unknown := db.query.modelPtr.GetUnknownColumns(columns); len(unknown) > 0 {
+			return fmt.Errorf("Error: columns %v have no matching field in %s", unknown, db.query.modelPtr.Name)
+		}
+	}
+
 	for rows.Next() {
 		mPtr, err := db.query.nextOrCreateModel()
 		if err != nil {
@@ -165,53 +403,258 @@ func (db *DB) executeSelectQuery() error {
 		if err := rows.Scan(pointers...); err != nil {
 			return err
 		}
+
+		db.query.modelPtr.FinishScan()
+		db.query.modelPtr.TakeSnapshot()
+		if err := db.callHook(db.query.modelPtr, afterFindHook); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db *DB) scanRowsToMaps(rows *sql.Rows, columns []string) error {
+	destVal := reflect.ValueOf(db.query.scanTo).Elem()
+
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		if err := rows.Scan(pointers...); err != nil {
+			return err
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, col := range columns {
+			row[col] = values[i]
+		}
+
+		if destVal.Kind() != reflect.Slice {
+			destVal.Set(reflect.ValueOf(row).Convert(destVal.Type()))
+			break
+		}
+
+		destVal.Set(reflect.Append(destVal, reflect.ValueOf(row).Convert(destVal.Type().Elem())))
+	}
+
+	return nil
+}
+
+func (db *DB) scanRowsToScalars(rows *sql.Rows, columns []string) error {
+	if len(columns) != 1 {
+		return fmt.Errorf("Error: expected single column, found %d", len(columns))
+	}
+
+	destVal := reflect.ValueOf(db.query.scanTo).Elem()
+	elemType := destVal.Type().Elem()
+
+	for rows.Next() {
+		elem := reflect.New(elemType)
+		if err := rows.Scan(elem.Interface()); err != nil {
+			return err
+		}
+
+		destVal.Set(reflect.Append(destVal, elem.Elem()))
 	}
-	rows.Close()
 
 	return nil
 }
 
 func (db *DB) executeInsertQuery() error {
+	var total int64
 	for db.query.nextModel() != nil {
+		if err := db.callHook(db.query.modelPtr, beforeInsertHook); err != nil {
+			return err
+		}
+
 		if err := db.query.prepareInsertQuery(); err != nil {
 			return err
 		}
 
-		_, err := db.Exec(db.query.SQL, db.query.args...)
+		affected, err := db.execRowsAffected(false)
 		if err != nil {
 			return err
 		}
+
+		total += affected
+		if err := db.callHook(db.query.modelPtr, afterInsertHook); err != nil {
+			return err
+		}
 	}
 
+	db.setRowsAffected(total)
 	return nil
 }
 
 func (db *DB) executeUpdateQuery() error {
+	var total int64
 	for db.query.nextModel() != nil {
+		// Before hook may modify the model, so it is called before checking modification
+		if err := db.callHook(db.query.modelPtr, beforeUpdateHook); err != nil {
+			return err
+		}
+
+		// Skip tracked model which has no modified column to update, e.g. only omitted column has been modified
+		if db.query.updateValues == nil && len(db.query.updateColumns) == 0 && db.query.modelPtr.IsTracked() {
+			cols, _, err := db.query.modelPtr.GetUpdateColumnNamesAndValues(nil, db.query.omitColumns)
+			if err != nil {
+				return err
+			}
+
+			if len(cols) == 0 {
+				continue
+			}
+		}
+
 		if err := db.query.prepareUpdateQuery(); err != nil {
 			return err
 		}
 
-		_, err := db.Exec(db.query.SQL, db.query.args...)
+		// Version is read before the query, as RETURNING clause may overwrite the field
+		var version int64
+		if db.query.isVersionedUpdate() {
+			version, _ = db.query.modelPtr.GetVersion()
+		}
+
+		affected, err := db.execRowsAffected(false)
 		if err != nil {
 			return err
 		}
+
+		if db.query.isVersionedUpdate() {
+			if affected == 0 {
+				db.setRowsAffected(total)
+				return ErrStaleObject
+			}
+
+			if err := db.query.modelPtr.SetVersion(version + 1); err != nil {
+				return err
+			}
+		}
+
+		total += affected
+		db.takeUpdateSnapshot()
+
+		if err := db.callHook(db.query.modelPtr, afterUpdateHook); err != nil {
+			return err
+		}
 	}
 
+	db.setRowsAffected(total)
 	return nil
 }
 
+// Record the updated columns as the new snapshot of tracked model, UpdateMap does not change the model so it is skipped
+func (db *DB) takeUpdateSnapshot() {
+	if db.query.updateValues != nil {
+		return
+	}
+
+	if len(db.query.updateColumns) == 0 && len(db.query.omitColumns) == 0 {
+		db.query.modelPtr.TakeSnapshot()
+		return
+	}
+
+	cols, _, err := db.query.modelPtr.GetUpdateColumnNamesAndValues(db.query.updateColumns, db.query.omitColumns)
+	if err == nil && len(cols) > 0 {
+		db.query.modelPtr.TakeSnapshot(cols...)
+	}
+}
+
+// Execute prepared query and return number of affected rows
+//	Query with RETURNING clause scan the returned rows to the current model, or to all models if scanAll is true
+func (db *DB) execRowsAffected(scanAll bool) (int64, error) {
+	if len(db.query.returning) > 0 {
+		return db.execReturningQuery(scanAll)
+	}
+
+	result, err := db.Exec(db.query.SQL, db.query.args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (db *DB) execReturningQuery(scanAll bool) (int64, error) {
+	rows, err := db.Query(db.query.SQL, db.query.args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+
+	var affected int64
+	for rows.Next() {
+		affected++
+
+		m := db.query.modelPtr
+		if scanAll {
+			if m, err = db.query.nextOrCreateModel(); err != nil {
+				return 0, err
+			}
+		} else if affected > 1 {
+			m = nil
+		}
+
+		// Returned rows without model to scan to are only counted
+		if m == nil {
+			continue
+		}
+
+		if err := rows.Scan(m.GetScanPtrByColumnNames(columns)...); err != nil {
+			return 0, err
+		}
+
+		m.FinishScan()
+	}
+
+	return affected, rows.Err()
+}
+
+// Store total number of affected rows to the destination of RowsAffected option
+func (db *DB) setRowsAffected(affected int64) {
+	if db.query.rowsAffected != nil {
+		*db.query.rowsAffected = affected
+	}
+}
+
 func (db *DB) executeDeleteQuery() error {
+	var total int64
 	for db.query.nextModel() != nil {
+		if err := db.callHook(db.query.modelPtr, beforeDeleteHook); err != nil {
+			return err
+		}
+
 		if err := db.query.prepareDeleteQuery(); err != nil {
 			return err
 		}
 
-		_, err := db.Exec(db.query.SQL, db.query.args...)
+		affected, err := db.execRowsAffected(false)
 		if err != nil {
 			return err
 		}
+
+		if db.query.isSoftDelete() && affected > 0 {
+			if err := db.query.modelPtr.SetSoftDeleted(db.query.softDeletedAt); err != nil {
+				return err
+			}
+		}
+
+		total += affected
+		if err := db.callHook(db.query.modelPtr, afterDeleteHook); err != nil {
+			return err
+		}
 	}
 
+	db.setRowsAffected(total)
 	return nil
 }
@@ -1,6 +1,7 @@
 package fury_test
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -284,6 +285,34 @@ func TestUpdateWhereQuery(t *testing.T) {
 	}
 }
 
+func TestUpdatePartialQuery(t *testing.T) {
+	if err := db.Update(&Account{UserID: 9, Email: "partial@test.com"}, fury.Columns("email")); err != nil {
+		t.Error(err)
+	}
+
+	if err := db.UpdateMap(&Account{UserID: 9}, map[string]interface{}{"username": "partial9"}); err != nil {
+		t.Error(err)
+	}
+
+	have := &Account{UserID: 9}
+	if err := db.First(have); err != nil {
+		t.Error(err)
+	}
+
+	want := &Account{
+		UserID:    9,
+		Username:  "partial9",
+		Password:  "test9",
+		Email:     "partial@test.com",
+		CreatedOn: time.Date(2016, 06, 22, 19, 10, 25, 0, time.FixedZone("", 0)),
+		LastLogin: time.Date(2016, 06, 22, 19, 10, 25, 0, time.FixedZone("", 0)),
+	}
+
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("Error: expected %v, found %v", want, have)
+	}
+}
+
 func TestDeleteQuery(t *testing.T) {
 	type queryFunc func(out interface{}, opts ...fury.QueryOption) error
 	type optionFunc func(conditions interface{}) fury.QueryOption
@@ -348,3 +377,399 @@ func TestDeleteWhereQuery(t *testing.T) {
 		}
 	}
 }
+
+func TestSetBasedUpdateDeleteQuery(t *testing.T) {
+	where := fury.Where(fury.And(fury.IsGreaterThanOrEqualsTo("userid", 11), fury.IsLessThanOrEqualsTo("userid", 13)))
+
+	updated, err := db.UpdateWhere(&Account{}, fury.Set{"password": "reset"}, where)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if updated != 3 {
+		t.Errorf("Error: expected %v, found %v", 3, updated)
+	}
+
+	deleted, err := db.DeleteWhere(&Account{}, fury.Where(fury.IsEqualsTo("password", "reset")), fury.Where(fury.IsEqualsTo("userid", 13)))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if deleted != 1 {
+		t.Errorf("Error: expected %v, found %v", 1, deleted)
+	}
+
+	if _, err := db.DeleteWhere(&Account{}); err == nil {
+		t.Error("Expected error found nil")
+	}
+}
+
+func TestReturningQuery(t *testing.T) {
+	var affected int64
+	account := &Account{UserID: 12, Password: "returned"}
+	if err := db.Update(account, fury.Columns("password"), fury.Returning("*"), fury.RowsAffected(&affected)); err != nil {
+		t.Error(err)
+	}
+
+	if affected != 1 || account.Email != "test12@test.com" {
+		t.Errorf("Error: expected %v and %v, found %v and %v", 1, "test12@test.com", affected, account.Email)
+	}
+
+	deleted := []*Account{}
+	count, err := db.DeleteWhere(&deleted, fury.Where(fury.IsEqualsTo("password", "returned")), fury.Returning("userid"))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if count != 1 || len(deleted) != 1 || deleted[0].UserID != 12 {
+		t.Errorf("Error: expected %v deleted record with userid %v, found %v", 1, 12, deleted)
+	}
+}
+
+func TestCountQuery(t *testing.T) {
+	cases := []struct {
+		have interface{}
+		opts []fury.QueryOption
+		want int64
+	}{
+		{&Account{}, []fury.QueryOption{fury.Where(fury.IsLessThanOrEqualsTo("userid", 4))}, 4},
+		{&Account{UserID: 1}, []fury.QueryOption{}, 1},
+	}
+
+	for _, tc := range cases {
+		have, err := db.Count(tc.have, tc.opts...)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if have != tc.want {
+			t.Errorf("Error: expected %v, found %v", tc.want, have)
+		}
+	}
+}
+
+func TestExistsQuery(t *testing.T) {
+	cases := []struct {
+		username string
+		want     bool
+	}{
+		{"test1", true},
+		{"notexist", false},
+	}
+
+	for _, tc := range cases {
+		have, err := db.Exists(&Account{}, fury.Where(fury.IsEqualsTo("username", tc.username)))
+		if err != nil {
+			t.Error(err)
+		}
+
+		if have != tc.want {
+			t.Errorf("Error: expected %v, found %v", tc.want, have)
+		}
+	}
+}
+
+func TestAggregateQuery(t *testing.T) {
+	sum, err := db.Sum(&Account{}, "userid", fury.Where(fury.IsLessThanOrEqualsTo("userid", 4)))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if sum != 10 {
+		t.Errorf("Error: expected %v, found %v", 10, sum)
+	}
+
+	var max int
+	if err := db.Max(&Account{}, "userid", &max, fury.Where(fury.IsLessThanOrEqualsTo("userid", 4))); err != nil {
+		t.Error(err)
+	}
+
+	if max != 4 {
+		t.Errorf("Error: expected %v, found %v", 4, max)
+	}
+}
+
+type AccountSummary struct {
+	UserID int
+	Email  string
+}
+
+func TestPluckQuery(t *testing.T) {
+	have := []string{}
+	want := []string{"test1@test.com", "test2@test.com"}
+
+	if err := db.Pluck(&have, "email", fury.Table("account"), fury.Where(fury.IsLessThanOrEqualsTo("userid", 2)), fury.OrderBy("userid")); err != nil {
+		t.Error(err)
+	}
+
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("Error: expected %v, found %v", want, have)
+	}
+}
+
+func TestFindMapQuery(t *testing.T) {
+	have := []map[string]interface{}{}
+	want := []map[string]interface{}{
+		{"userid": int64(1), "username": "test1"},
+	}
+
+	if err := db.Find(&have, fury.Table("account"), fury.Select("userid", "username"), fury.Where(fury.IsEqualsTo("userid", 1))); err != nil {
+		t.Error(err)
+	}
+
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("Error: expected %v, found %v", want, have)
+	}
+}
+
+func TestFindResultStructQuery(t *testing.T) {
+	have := []*AccountSummary{}
+	want := []*AccountSummary{
+		&AccountSummary{UserID: 1, Email: "test1@test.com"},
+	}
+
+	if err := db.Find(&have, fury.Table("account"), fury.Select("userid", "username", "email"), fury.Where(fury.IsEqualsTo("userid", 1))); err != nil {
+		t.Error(err)
+	}
+
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("Error: expected %v, found %v", want, have)
+	}
+
+	if err := db.Find(&[]*AccountSummary{}, fury.Table("account"), fury.StrictColumns()); err == nil {
+		t.Error("Expected error found nil")
+	}
+}
+
+func TestRawQuery(t *testing.T) {
+	have := []*Account{}
+	want := []*Account{
+		&Account{
+			UserID:    2,
+			Username:  "test2",
+			Password:  "test2",
+			Email:     "test2@test.com",
+			CreatedOn: time.Date(2016, 06, 22, 19, 10, 25, 0, time.FixedZone("", 0)),
+			LastLogin: time.Date(2016, 06, 22, 19, 10, 25, 0, time.FixedZone("", 0)),
+		},
+	}
+
+	if err := db.Raw("SELECT * FROM account WHERE userid = ? OR username = ?", 2, "test2").Scan(&have); err != nil {
+		t.Error(err)
+	}
+
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("Error: expected %v, found %v", want, have)
+	}
+
+	res, err := db.Raw("UPDATE account SET lastlogin = lastlogin WHERE userid = ?", 2).Exec()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if affected, _ := res.RowsAffected(); affected != 1 {
+		t.Errorf("Error: expected %v, found %v", 1, affected)
+	}
+}
+
+func TestRowsQuery(t *testing.T) {
+	rows, err := db.Rows(&Account{}, fury.Where(fury.IsLessThanOrEqualsTo("userid", 3)), fury.OrderBy("userid"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	have := []int{}
+	account := &Account{}
+	for rows.Next() {
+		if err := rows.Scan(account); err != nil {
+			t.Error(err)
+		}
+		have = append(have, account.UserID)
+	}
+
+	if err := rows.Err(); err != nil {
+		t.Error(err)
+	}
+
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(want, have) {
+		t.Errorf("Error: expected %v, found %v", want, have)
+	}
+}
+
+func TestFindEachQuery(t *testing.T) {
+	have := []string{}
+	err := db.FindEach(&Account{}, func(account *Account) error {
+		have = append(have, account.Username)
+		if len(have) == 2 {
+			return errors.New("stop")
+		}
+		return nil
+	}, fury.Where(fury.IsLessThanOrEqualsTo("userid", 3)), fury.OrderBy("userid"))
+
+	if err == nil || err.Error() != "stop" {
+		t.Errorf("Error: expected callback error, found %v", err)
+	}
+
+	if want := []string{"test1", "test2"}; !reflect.DeepEqual(want, have) {
+		t.Errorf("Error: expected %v, found %v", want, have)
+	}
+}
+
+func TestFindInBatchesQuery(t *testing.T) {
+	have := [][]int{}
+	accounts := []*Account{}
+	err := db.FindInBatches(&accounts, 2, func(batch *[]*Account) error {
+		ids := []int{}
+		for _, account := range *batch {
+			ids = append(ids, account.UserID)
+		}
+		have = append(have, ids)
+		return nil
+	}, fury.Where(fury.IsLessThanOrEqualsTo("userid", 5)))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if want := [][]int{{1, 2}, {3, 4}, {5}}; !reflect.DeepEqual(want, have) {
+		t.Errorf("Error: expected %v, found %v", want, have)
+	}
+}
+
+func TestFindPageQuery(t *testing.T) {
+	where := fury.Where(fury.IsLessThanOrEqualsTo("userid", 5))
+
+	first := []*Account{}
+	cursors, err := db.FindPage(&first, where, fury.Paginate("", 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(first) != 2 || first[0].UserID != 1 || cursors.Next == "" || cursors.Prev != "" {
+		t.Errorf("Error: unexpected first page %v with cursors %v", first, cursors)
+	}
+
+	second := []*Account{}
+	cursors, err = db.FindPage(&second, where, fury.Paginate(cursors.Next, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(second) != 2 || second[0].UserID != 3 || cursors.Next == "" || cursors.Prev == "" {
+		t.Errorf("Error: unexpected second page %v with cursors %v", second, cursors)
+	}
+
+	back := []*Account{}
+	if _, err = db.FindPage(&back, where, fury.Paginate(cursors.Prev, 2)); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(first, back) {
+		t.Errorf("Error: expected %v, found %v", first, back)
+	}
+}
+
+func TestPageQuery(t *testing.T) {
+	accounts := []*Account{}
+	total, err := db.Page(&accounts, 2, 2, fury.Where(fury.IsLessThanOrEqualsTo("userid", 5)), fury.OrderBy("userid"))
+	if err != nil {
+		t.Error(err)
+	}
+
+	if total != 5 || len(accounts) != 2 || accounts[0].UserID != 3 {
+		t.Errorf("Error: unexpected page %v with total %v", accounts, total)
+	}
+}
+
+type AccountWithOther struct {
+	UserID int
+	Other  AccountSummary
+}
+
+func TestJoinQuery(t *testing.T) {
+	have := []*AccountWithOther{}
+	want := []*AccountWithOther{
+		&AccountWithOther{UserID: 1, Other: AccountSummary{UserID: 2, Email: "test2@test.com"}},
+	}
+
+	err := db.Find(&have,
+		fury.Table("account AS a"),
+		fury.Join("account AS b", fury.IsEqualsTo("b.userid", fury.Add(fury.Col("a.userid"), 1))),
+		fury.Select("a.userid", `b.userid AS "other.userid"`, `b.email AS "other.email"`),
+		fury.Where(fury.IsEqualsTo("a.userid", 1)),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("Error: expected %v, found %v", want, have)
+	}
+}
+
+func TestUnionQuery(t *testing.T) {
+	first, err := fury.NewQuery(&[]*Account{}, fury.Where(fury.IsLessThanOrEqualsTo("userid", 2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := fury.NewQuery(&[]*Account{}, fury.Where(fury.IsEqualsTo("username", "test4")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	have := []*Account{}
+	if err := db.Find(&have, fury.Union(first, second), fury.OrderBy("userid DESC"), fury.Limit(2)); err != nil {
+		t.Error(err)
+	}
+
+	if len(have) != 2 || have[0].UserID != 4 || have[1].UserID != 2 {
+		t.Errorf("Error: unexpected union result %v", have)
+	}
+}
+
+func TestLockInTransaction(t *testing.T) {
+	err := db.Transaction(func(tx *fury.DB) error {
+		accounts := []*Account{}
+		if err := tx.Find(&accounts, fury.Where(fury.IsLessThanOrEqualsTo("userid", 2)), fury.OrderBy("userid"), fury.ForUpdate(), fury.SkipLocked()); err != nil {
+			return err
+		}
+
+		if len(accounts) != 2 {
+			t.Errorf("Error: expected %v records, found %v", 2, len(accounts))
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	if err := db.Find(&[]*Account{}, fury.ForUpdate()); err == nil {
+		t.Error("Expected error found nil")
+	}
+}
+
+type AuditedAccount struct {
+	UserID int `fury:"primary_key"`
+	Email  string
+	Loaded bool
+}
+
+func (aa *AuditedAccount) AfterFind(db *fury.DB) error {
+	aa.Loaded = true
+	return nil
+}
+
+func TestAfterFindHook(t *testing.T) {
+	accounts := []*AuditedAccount{}
+	if err := db.Find(&accounts, fury.Table("account"), fury.Where(fury.IsLessThanOrEqualsTo("userid", 2))); err != nil {
+		t.Error(err)
+	}
+
+	if len(accounts) != 2 || !accounts[0].Loaded || !accounts[1].Loaded {
+		t.Errorf("Error: expected %v loaded accounts, found %v", 2, accounts)
+	}
+}
@@ -6,25 +6,112 @@ import (
 	"reflect"
 )
 
+// sqlStringer is implemented by every value that renders itself as SQL instead of a bound argument
+type sqlStringer interface {
+	ToString() (string, []interface{}, error)
+}
+
 // Expression struct to store query expression
 type Expression struct {
 	operator string
-	operand1 string
+	operand1 interface{}
 	operand2 interface{}
 }
 
 // ToString method convert Expression struct to string and slice of arguments
 func (e *Expression) ToString() (string, []interface{}, error) {
-	if e.operator == "" || e.operand1 == "" || e.operand2 == nil {
+	if e.operator == "" || e.operand1 == nil || e.operand1 == "" || e.operand2 == nil {
 		return "", nil, errors.New("Error creating expression: missing operator or operand")
 	}
-	args := []interface{}{e.operand2}
 
-	return fmt.Sprintf("%s %s ?", e.operand1, e.operator), args, nil
+	// String left operand is always column name, right operand is bound as argument unless it is SQL value
+	left, args, err := operandToString(e.operand1, true)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Empty list is never matched by IN, so NOT IN of empty list always match
+	if e.operator == "NOT IN" && isEmptyList(e.operand2) {
+		return "1 = 1", []interface{}{}, nil
+	}
+
+	var right string
+	var rightArgs []interface{}
+	if e.operator == "IN" || e.operator == "NOT IN" {
+		right, rightArgs, err = listOperandToString(e.operand2)
+	} else {
+		right, rightArgs, err = operandToString(e.operand2, false)
+	}
+
+	if err != nil {
+		return "", nil, err
+	}
+	args = append(args, rightArgs...)
+
+	return fmt.Sprintf("%s %s %s", left, e.operator, right), args, nil
+}
+
+// Convert single operand to string and slice of arguments
+// 	When isIdentifier is true, string operand is written as is instead of bound as argument
+func operandToString(operand interface{}, isIdentifier bool) (string, []interface{}, error) {
+	if exp, ok := operand.(sqlStringer); ok {
+		return exp.ToString()
+	}
+
+	if str, ok := operand.(string); ok && isIdentifier {
+		return str, []interface{}{}, nil
+	}
+
+	return "?", []interface{}{operand}, nil
+}
+
+// Convert operand of IN expression to parenthesized list, slice operand is expanded to one argument per element
+func listOperandToString(operand interface{}) (string, []interface{}, error) {
+	if exp, ok := operand.(sqlStringer); ok {
+		return exp.ToString()
+	}
+
+	reflectVal := reflect.ValueOf(operand)
+	if (reflectVal.Kind() != reflect.Slice && reflectVal.Kind() != reflect.Array) || reflectVal.Type().Elem().Kind() == reflect.Uint8 {
+		return "(?)", []interface{}{operand}, nil
+	}
+
+	// Empty list never match any value
+	if reflectVal.Len() == 0 {
+		return "(NULL)", []interface{}{}, nil
+	}
+
+	out := ""
+	args := []interface{}{}
+	for i := 0; i < reflectVal.Len(); i++ {
+		if i > 0 {
+			out += ", "
+		}
+
+		elemStr, elemArgs, err := operandToString(reflectVal.Index(i).Interface(), false)
+		if err != nil {
+			return "", nil, err
+		}
+
+		out += elemStr
+		args = append(args, elemArgs...)
+	}
+
+	return fmt.Sprintf("(%s)", out), args, nil
+}
+
+// Check if operand of IN expression is empty slice or array, []byte is single value
+func isEmptyList(operand interface{}) bool {
+	reflectVal := reflect.ValueOf(operand)
+	if (reflectVal.Kind() != reflect.Slice && reflectVal.Kind() != reflect.Array) || reflectVal.Type().Elem().Kind() == reflect.Uint8 {
+		return false
+	}
+
+	return reflectVal.Len() == 0
 }
 
 // newExpression as factory function for Expression struct
-func newExpression(operator, operand1 string, operand2 interface{}) *Expression {
+func newExpression(operator string, operand1, operand2 interface{}) *Expression {
 	return &Expression{
 		operator: operator,
 		operand1: operand1,
@@ -34,40 +121,90 @@ func newExpression(operator, operand1 string, operand2 interface{}) *Expression
 
 // IsGreaterThan expression
 // 	This function will generate expression equivalent to 'operand1 > operand2'
-func IsGreaterThan(operand1 string, operand2 interface{}) *Expression {
+func IsGreaterThan(operand1, operand2 interface{}) *Expression {
 	return newExpression(">", operand1, operand2)
 }
 
 // IsGreaterThanOrEqualsTo expression
 // 	This function will generate expression equivalent to 'operand1 >= operand2'
-func IsGreaterThanOrEqualsTo(operand1 string, operand2 interface{}) *Expression {
+func IsGreaterThanOrEqualsTo(operand1, operand2 interface{}) *Expression {
 	return newExpression(">=", operand1, operand2)
 }
 
 // IsLessThan expression
 // 	This function will generate expression equivalent to 'operand1 < operand2'
-func IsLessThan(operand1 string, operand2 interface{}) *Expression {
+func IsLessThan(operand1, operand2 interface{}) *Expression {
 	return newExpression("<", operand1, operand2)
 }
 
 // IsLessThanOrEqualsTo expression
 // 	This function will generate expression equivalent to 'operand1 <= operand2'
-func IsLessThanOrEqualsTo(operand1 string, operand2 interface{}) *Expression {
+func IsLessThanOrEqualsTo(operand1, operand2 interface{}) *Expression {
 	return newExpression("<=", operand1, operand2)
 }
 
 // IsEqualsTo expression
 // 	This function will generate expression equivalent to 'operand1 = operand2'
-func IsEqualsTo(operand1 string, operand2 interface{}) *Expression {
+func IsEqualsTo(operand1, operand2 interface{}) *Expression {
 	return newExpression("=", operand1, operand2)
 }
 
 // IsNotEqualsTo expression
 // 	This function will generate expression equivalent to 'operand1 <> operand2'
-func IsNotEqualsTo(operand1 string, operand2 interface{}) *Expression {
+func IsNotEqualsTo(operand1, operand2 interface{}) *Expression {
 	return newExpression("<>", operand1, operand2)
 }
 
+// IsIn expression
+// 	This function will generate expression equivalent to 'operand1 IN (operand2)'
+//	Operand2 may be slice of values or subquery created by NewQuery
+func IsIn(operand1, operand2 interface{}) *Expression {
+	return newExpression("IN", operand1, operand2)
+}
+
+// IsNotIn expression
+// 	This function will generate expression equivalent to 'operand1 NOT IN (operand2)'
+//	Operand2 may be slice of values or subquery created by NewQuery
+func IsNotIn(operand1, operand2 interface{}) *Expression {
+	return newExpression("NOT IN", operand1, operand2)
+}
+
+// ExistsExpression struct to store EXISTS condition of subquery
+type ExistsExpression struct {
+	not      bool
+	subquery *Query
+}
+
+// ToString method convert ExistsExpression struct to string and slice of arguments
+func (ee *ExistsExpression) ToString() (string, []interface{}, error) {
+	if ee.subquery == nil {
+		return "", nil, errors.New("Error creating exists expression: missing subquery")
+	}
+
+	subquery, args, err := ee.subquery.ToString()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if ee.not {
+		return fmt.Sprintf("NOT EXISTS %s", subquery), args, nil
+	}
+
+	return fmt.Sprintf("EXISTS %s", subquery), args, nil
+}
+
+// Exists expression
+// 	This function will generate expression equivalent to 'EXISTS (subquery)'
+func Exists(subquery *Query) *ExistsExpression {
+	return &ExistsExpression{subquery: subquery}
+}
+
+// NotExists expression
+// 	This function will generate expression equivalent to 'NOT EXISTS (subquery)'
+func NotExists(subquery *Query) *ExistsExpression {
+	return &ExistsExpression{not: true, subquery: subquery}
+}
+
 // LogicalExpression struct to store expression with logical condition as tree
 type LogicalExpression struct {
 	logicalOperator string
@@ -163,3 +300,159 @@ func And(operands ...interface{}) *LogicalExpression {
 func Or(operands ...interface{}) *LogicalExpression {
 	return newLogicalExpression("OR", operands...)
 }
+
+// Column struct to store column identifier used as expression operand
+type Column struct {
+	name string
+}
+
+// ToString method convert Column struct to string, column never has argument
+func (c *Column) ToString() (string, []interface{}, error) {
+	if c.name == "" {
+		return "", nil, errors.New("Error creating column: missing column name")
+	}
+
+	return c.name, []interface{}{}, nil
+}
+
+// Col expression
+// 	Return column identifier, written as is in the query instead of bound as argument
+func Col(name string) *Column {
+	return &Column{name: name}
+}
+
+// ArithmeticExpression struct to store arithmetic operation between two operands
+type ArithmeticExpression struct {
+	operator string
+	operand1 interface{}
+	operand2 interface{}
+}
+
+// ToString method convert ArithmeticExpression struct to string and slice of arguments
+func (ae *ArithmeticExpression) ToString() (string, []interface{}, error) {
+	if ae.operator == "" || ae.operand1 == nil || ae.operand2 == nil {
+		return "", nil, errors.New("Error creating arithmetic expression: missing operator or operand")
+	}
+
+	left, args, err := operandToString(ae.operand1, false)
+	if err != nil {
+		return "", nil, err
+	}
+
+	right, rightArgs, err := operandToString(ae.operand2, false)
+	if err != nil {
+		return "", nil, err
+	}
+	args = append(args, rightArgs...)
+
+	return fmt.Sprintf("(%s %s %s)", left, ae.operator, right), args, nil
+}
+
+// newArithmeticExpression is the factory function for ArithmeticExpression struct
+func newArithmeticExpression(operator string, operand1, operand2 interface{}) *ArithmeticExpression {
+	return &ArithmeticExpression{
+		operator: operator,
+		operand1: operand1,
+		operand2: operand2,
+	}
+}
+
+// Add expression
+// 	Return arithmetic expression equivalent to '(operand1 + operand2)'
+func Add(operand1, operand2 interface{}) *ArithmeticExpression {
+	return newArithmeticExpression("+", operand1, operand2)
+}
+
+// Sub expression
+// 	Return arithmetic expression equivalent to '(operand1 - operand2)'
+func Sub(operand1, operand2 interface{}) *ArithmeticExpression {
+	return newArithmeticExpression("-", operand1, operand2)
+}
+
+// Mul expression
+// 	Return arithmetic expression equivalent to '(operand1 * operand2)'
+func Mul(operand1, operand2 interface{}) *ArithmeticExpression {
+	return newArithmeticExpression("*", operand1, operand2)
+}
+
+// Div expression
+// 	Return arithmetic expression equivalent to '(operand1 / operand2)'
+func Div(operand1, operand2 interface{}) *ArithmeticExpression {
+	return newArithmeticExpression("/", operand1, operand2)
+}
+
+// FunctionCall struct to store SQL function call
+type FunctionCall struct {
+	name string
+	args []interface{}
+}
+
+// ToString method convert FunctionCall struct to string and slice of arguments
+func (fc *FunctionCall) ToString() (string, []interface{}, error) {
+	if fc.name == "" {
+		return "", nil, errors.New("Error creating function call: missing function name")
+	}
+
+	out := ""
+	args := []interface{}{}
+	for i, arg := range fc.args {
+		if i > 0 {
+			out += ", "
+		}
+
+		argStr, argArgs, err := operandToString(arg, false)
+		if err != nil {
+			return "", nil, err
+		}
+
+		out += argStr
+		args = append(args, argArgs...)
+	}
+
+	return fmt.Sprintf("%s(%s)", fc.name, out), args, nil
+}
+
+// Func expression
+// 	Return SQL function call equivalent to 'name(args[0], args[1], ...)', use Col for column arguments
+func Func(name string, args ...interface{}) *FunctionCall {
+	return &FunctionCall{
+		name: name,
+		args: args,
+	}
+}
+
+// TupleExpression struct to store row value of several operands
+type TupleExpression struct {
+	operands []interface{}
+}
+
+// ToString method convert TupleExpression struct to string and slice of arguments
+func (te *TupleExpression) ToString() (string, []interface{}, error) {
+	if len(te.operands) == 0 {
+		return "", nil, errors.New("Error creating tuple: missing operand")
+	}
+
+	out := ""
+	args := []interface{}{}
+	for i, operand := range te.operands {
+		if i > 0 {
+			out += ", "
+		}
+
+		operandStr, operandArgs, err := operandToString(operand, false)
+		if err != nil {
+			return "", nil, err
+		}
+
+		out += operandStr
+		args = append(args, operandArgs...)
+	}
+
+	return fmt.Sprintf("(%s)", out), args, nil
+}
+
+// Tuple expression
+// 	Return row value equivalent to '(operands[0], operands[1], ...)', use Col for column operands
+func Tuple(operands ...interface{}) *TupleExpression {
+	return &TupleExpression{operands: operands}
+}
@@ -1,6 +1,7 @@
 package fury_test
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/nandaryanizar/fury"
@@ -174,3 +175,101 @@ func TestLogicalExpressionToString(t *testing.T) {
 		}
 	}
 }
+
+func TestColumnOperandExpression(t *testing.T) {
+	cases := []struct {
+		have     *fury.Expression
+		want     string
+		wantArgs []interface{}
+	}{
+		{fury.IsGreaterThan("updated_at", fury.Col("created_on")), "updated_at > created_on", []interface{}{}},
+		{fury.IsEqualsTo(fury.Col("a.userid"), fury.Col("b.owner_id")), "a.userid = b.owner_id", []interface{}{}},
+		{fury.IsGreaterThanOrEqualsTo("balance", fury.Mul(fury.Col("limit"), 2)), "balance >= (limit * ?)", []interface{}{2}},
+		{fury.IsLessThan(fury.Add(fury.Col("a"), 1), fury.Sub(fury.Col("b"), fury.Div(fury.Col("c"), 2))), "(a + ?) < (b - (c / ?))", []interface{}{1, 2}},
+		{fury.IsEqualsTo(fury.Func("LOWER", fury.Col("email")), "some@test.com"), "LOWER(email) = ?", []interface{}{"some@test.com"}},
+		{fury.IsLessThan("lastlogin", fury.Func("NOW")), "lastlogin < NOW()", []interface{}{}},
+	}
+
+	for _, tc := range cases {
+		have, args, err := tc.have.ToString()
+		if err != nil {
+			t.Error(err)
+		}
+
+		if have != tc.want || !reflect.DeepEqual(args, tc.wantArgs) {
+			t.Errorf("Error: expected %v and %v, found %v and %v", tc.want, tc.wantArgs, have, args)
+		}
+	}
+}
+
+func TestColumnOperandExpressionError(t *testing.T) {
+	cases := []struct {
+		have *fury.Expression
+	}{
+		{fury.IsEqualsTo("key", fury.Col(""))},
+		{fury.IsEqualsTo("key", fury.Add(fury.Col("a"), nil))},
+		{fury.IsEqualsTo("key", fury.Func(""))},
+	}
+
+	for _, tc := range cases {
+		if _, _, err := tc.have.ToString(); err == nil {
+			t.Error("Expected error found nil")
+		}
+	}
+}
+
+func TestTupleExpression(t *testing.T) {
+	cases := []struct {
+		have     *fury.Expression
+		want     string
+		wantArgs []interface{}
+	}{
+		{fury.IsGreaterThan(fury.Tuple(fury.Col("a"), fury.Col("b")), fury.Tuple(1, "2")), "(a, b) > (?, ?)", []interface{}{1, "2"}},
+		{fury.IsEqualsTo(fury.Tuple(fury.Col("a")), fury.Tuple(fury.Col("b"))), "(a) = (b)", []interface{}{}},
+	}
+
+	for _, tc := range cases {
+		have, args, err := tc.have.ToString()
+		if err != nil {
+			t.Error(err)
+		}
+
+		if have != tc.want || !reflect.DeepEqual(args, tc.wantArgs) {
+			t.Errorf("Error: expected %v and %v, found %v and %v", tc.want, tc.wantArgs, have, args)
+		}
+	}
+
+	if _, _, err := fury.Tuple().ToString(); err == nil {
+		t.Error("Expected error found nil")
+	}
+}
+
+func TestIsInExpression(t *testing.T) {
+	cases := []struct {
+		have     *fury.Expression
+		want     string
+		wantArgs []interface{}
+	}{
+		{fury.IsIn("key", []int{1, 2, 3}), "key IN (?, ?, ?)", []interface{}{1, 2, 3}},
+		{fury.IsIn("key", []interface{}{"a", fury.Col("other")}), "key IN (?, other)", []interface{}{"a"}},
+		{fury.IsIn("key", []string{}), "key IN (NULL)", []interface{}{}},
+		{fury.IsNotIn("key", []string{}), "1 = 1", []interface{}{}},
+		{fury.IsIn("key", 1), "key IN (?)", []interface{}{1}},
+		{fury.IsIn("key", []byte("ab")), "key IN (?)", []interface{}{[]byte("ab")}},
+	}
+
+	for _, tc := range cases {
+		have, args, err := tc.have.ToString()
+		if err != nil {
+			t.Error(err)
+		}
+
+		if have != tc.want || !reflect.DeepEqual(args, tc.wantArgs) {
+			t.Errorf("Error: expected %v and %v, found %v and %v", tc.want, tc.wantArgs, have, args)
+		}
+	}
+
+	if _, _, err := fury.Exists(nil).ToString(); err == nil {
+		t.Error("Expected error found nil")
+	}
+}
@@ -0,0 +1,105 @@
+package fury
+
+import (
+	"github.com/nandaryanizar/fury/model"
+)
+
+// BeforeInserter interface
+// 	Model that implements this interface is called before inserted, returning error abort the insert
+type BeforeInserter interface {
+	BeforeInsert(db *DB) error
+}
+
+// AfterInserter interface
+// 	Model that implements this interface is called after inserted
+type AfterInserter interface {
+	AfterInsert(db *DB) error
+}
+
+// BeforeUpdater interface
+// 	Model that implements this interface is called before updated, returning error abort the update
+type BeforeUpdater interface {
+	BeforeUpdate(db *DB) error
+}
+
+// AfterUpdater interface
+// 	Model that implements this interface is called after updated
+type AfterUpdater interface {
+	AfterUpdate(db *DB) error
+}
+
+// BeforeDeleter interface
+// 	Model that implements this interface is called before deleted, returning error abort the delete
+type BeforeDeleter interface {
+	BeforeDelete(db *DB) error
+}
+
+// AfterDeleter interface
+// 	Model that implements this interface is called after deleted
+type AfterDeleter interface {
+	AfterDelete(db *DB) error
+}
+
+// AfterFinder interface
+// 	Model that implements this interface is called after scanned from query result
+type AfterFinder interface {
+	AfterFind(db *DB) error
+}
+
+type hook int
+
+const (
+	beforeInsertHook hook = iota
+	afterInsertHook
+	beforeUpdateHook
+	afterUpdateHook
+	beforeDeleteHook
+	afterDeleteHook
+	afterFindHook
+)
+
+// Call the hook of the model if implemented
+// 	Error returned by the hook abort the operation and roll back the transaction, if any
+func (db *DB) callHook(m *model.Model, h hook) error {
+	if m == nil || m.StructPtr == nil {
+		return nil
+	}
+
+	var err error
+	switch h {
+	case beforeInsertHook:
+		if hm, ok := m.StructPtr.(BeforeInserter); ok {
+			err = hm.BeforeInsert(db)
+		}
+	case afterInsertHook:
+		if hm, ok := m.StructPtr.(AfterInserter); ok {
+			err = hm.AfterInsert(db)
+		}
+	case beforeUpdateHook:
+		if hm, ok := m.StructPtr.(BeforeUpdater); ok {
+			err = hm.BeforeUpdate(db)
+		}
+	case afterUpdateHook:
+		if hm, ok := m.StructPtr.(AfterUpdater); ok {
+			err = hm.AfterUpdate(db)
+		}
+	case beforeDeleteHook:
+		if hm, ok := m.StructPtr.(BeforeDeleter); ok {
+			err = hm.BeforeDelete(db)
+		}
+	case afterDeleteHook:
+		if hm, ok := m.StructPtr.(AfterDeleter); ok {
+			err = hm.AfterDelete(db)
+		}
+	case afterFindHook:
+		if hm, ok := m.StructPtr.(AfterFinder); ok {
+			err = hm.AfterFind(db)
+		}
+	}
+
+	if err != nil && db.tx != nil {
+		db.tx.Rollback()
+	}
+
+	return err
+}
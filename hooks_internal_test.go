@@ -0,0 +1,84 @@
+package fury
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type HookedProfile struct {
+	UserID int `fury:"primary_key"`
+	Email  string
+}
+
+var hookCalls []string
+
+func (hp *HookedProfile) BeforeInsert(db *DB) error {
+	if hp.Email == "" {
+		return errors.New("Error: email is required")
+	}
+
+	hp.Email = strings.ToLower(hp.Email)
+	hookCalls = append(hookCalls, "BeforeInsert")
+	return nil
+}
+
+func (hp *HookedProfile) AfterInsert(db *DB) error {
+	hookCalls = append(hookCalls, "AfterInsert")
+	return nil
+}
+
+func (hp *HookedProfile) BeforeUpdate(db *DB) error {
+	hookCalls = append(hookCalls, "BeforeUpdate")
+	return nil
+}
+
+func (hp *HookedProfile) AfterUpdate(db *DB) error {
+	hookCalls = append(hookCalls, "AfterUpdate")
+	return nil
+}
+
+func (hp *HookedProfile) BeforeDelete(db *DB) error {
+	return errors.New("Error: profile cannot be deleted")
+}
+
+func TestModelHooks(t *testing.T) {
+	pool := &recordingConnectionPool{affected: 1}
+	db, err := ConnectMock(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hookCalls = nil
+	profiles := []*HookedProfile{
+		&HookedProfile{UserID: 1, Email: "Some@Test.com"},
+		&HookedProfile{UserID: 2, Email: "Other@Test.com"},
+	}
+
+	if err := db.Insert(profiles); err != nil {
+		t.Error(err)
+	}
+
+	if err := db.Update(profiles[0]); err != nil {
+		t.Error(err)
+	}
+
+	if err := db.Delete(profiles[0]); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	if err := db.Insert(&HookedProfile{UserID: 3}); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	wantCalls := []string{"BeforeInsert", "AfterInsert", "BeforeInsert", "AfterInsert", "BeforeUpdate", "AfterUpdate"}
+	if !reflect.DeepEqual(wantCalls, hookCalls) || profiles[1].Email != "other@test.com" {
+		t.Errorf("Error: expected %v, found %v", wantCalls, hookCalls)
+	}
+
+	// Aborted delete and insert must not execute any query
+	if len(pool.queries) != 3 {
+		t.Errorf("Error: expected %v queries, found %v", 3, pool.queries)
+	}
+}
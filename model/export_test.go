@@ -0,0 +1,10 @@
+package model
+
+import "reflect"
+
+// ResetSchemaCache remove every cached schema, used to measure model creation without cache
+func ResetSchemaCache() {
+	schemaCache.Lock()
+	schemaCache.schemas = make(map[reflect.Type]*schema)
+	schemaCache.Unlock()
+}
@@ -1,10 +1,15 @@
 package model
 
 import (
+	"database/sql/driver"
+	"fmt"
 	"reflect"
 	"strings"
+	"time"
 )
 
+var timeType = reflect.TypeOf(time.Time{})
+
 // Field struct
 type Field struct {
 	Properties      reflect.StructField
@@ -12,6 +17,17 @@ type Field struct {
 	IsPrimaryKey    bool
 	IsAutoIncrement bool
 	IsIgnored       bool
+	IsOmitEmpty     bool
+	IsVersion       bool
+	IsSoftDelete    bool
+	IsCreatedAt     bool
+	IsUpdatedAt     bool
+	IsEmbedded      bool
+	Prefix          string
+
+	column string
+	rules  []validationRule
+	tagErr error
 }
 
 // NewField create new field literal
@@ -29,7 +45,17 @@ func NewField(prop reflect.StructField, val reflect.Value) *Field {
 func (f *Field) processTagString() {
 	if tag := f.Properties.Tag.Get("fury"); tag != "" {
 		tags := strings.Split(tag, ",")
-		for _, val := range tags {
+		for i, val := range tags {
+			// Pattern may contain comma, so it takes the rest of the tag
+			if strings.HasPrefix(strings.ToLower(val), "pattern:") {
+				f.addValidationRule(strings.Join(tags[i:], ","))
+				break
+			}
+
+			if isValidationRule(val) {
+				f.addValidationRule(val)
+			}
+
 			if strings.ToLower(val) == "primary_key" {
 				f.IsPrimaryKey = true
 			}
@@ -37,11 +63,172 @@ func (f *Field) processTagString() {
 			if strings.ToLower(val) == "auto_increment" {
 				f.IsAutoIncrement = true
 			}
+
+			if strings.ToLower(val) == "omitempty" {
+				f.IsOmitEmpty = true
+			}
+
+			if strings.ToLower(val) == "version" {
+				f.IsVersion = true
+			}
+
+			if strings.ToLower(val) == "soft_delete" {
+				f.IsSoftDelete = true
+			}
+
+			if strings.ToLower(val) == "created_at" {
+				f.IsCreatedAt = true
+			}
+
+			if strings.ToLower(val) == "updated_at" {
+				f.IsUpdatedAt = true
+			}
+
+			if strings.ToLower(val) == "embedded" {
+				f.IsEmbedded = true
+			}
+
+			if strings.HasPrefix(strings.ToLower(val), "prefix:") {
+				f.Prefix = strings.ToLower(val[len("prefix:"):])
+			}
 		}
 	}
 }
 
+// ColumnName return column name of the field, field of embedded struct with prefix tag has the prefix prepended
+func (f *Field) ColumnName() string {
+	if f.column != "" {
+		return f.column
+	}
+
+	return strings.ToLower(f.Properties.Name)
+}
+
+// SetTime set value of time.Time or *time.Time field
+func (f *Field) SetTime(t time.Time) error {
+	switch f.Value.Type() {
+	case timeType:
+		f.Value.Set(reflect.ValueOf(t))
+	case reflect.PtrTo(timeType):
+		f.Value.Set(reflect.ValueOf(&t))
+	default:
+		return fmt.Errorf("Error: field %s must be time.Time or *time.Time, found %v", f.Properties.Name, f.Value.Type())
+	}
+
+	return nil
+}
+
 // CheckIfZeroValue check if value of the field is the zero value of the type of the field
+//	Nil pointer, slice or map and driver.Valuer which value is NULL, e.g. sql.NullString with false Valid, are zero value as well
 func (f *Field) CheckIfZeroValue() bool {
-	return reflect.Zero(f.Value.Type()).Interface() == f.Value.Interface()
+	return isZeroValue(f.Value) || isNullValue(f.Value)
+}
+
+// Check if the value is zero value of its type, non-comparable type such as slice and map is zero only if nil
+func isZeroValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Complex64, reflect.Complex128:
+		return v.Complex() == 0
+	case reflect.String:
+		return v.Len() == 0
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !isZeroValue(v.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !isZeroValue(v.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return v.IsNil()
+	}
+
+	return !v.IsValid()
+}
+
+// Check if the value is written as NULL, which is nil, nil pointer, slice or map, or driver.Valuer returning nil
+func isNullValue(v reflect.Value) bool {
+	if !v.IsValid() || isNilValue(v) {
+		return true
+	}
+
+	valuer, ok := getValuer(v)
+	if !ok {
+		return false
+	}
+
+	val, err := valuer.Value()
+	return err == nil && val == nil
+}
+
+// Get driver.Valuer implemented by the value or pointer to the value
+func getValuer(v reflect.Value) (driver.Valuer, bool) {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil, false
+	}
+
+	if valuer, ok := v.Interface().(driver.Valuer); ok {
+		return valuer, true
+	}
+
+	if v.CanAddr() {
+		valuer, ok := v.Addr().Interface().(driver.Valuer)
+		return valuer, ok
+	}
+
+	return nil, false
+}
+
+// BindValue return the value to be bound as query argument
+//	Pointer to the value is returned when only the pointer implements driver.Valuer, so the value is written as it is checked by isNullValue
+func BindValue(v reflect.Value) interface{} {
+	if _, ok := v.Interface().(driver.Valuer); !ok && v.CanAddr() {
+		if valuer, ok := v.Addr().Interface().(driver.Valuer); ok {
+			return valuer
+		}
+	}
+
+	return v.Interface()
+}
+
+// nullTimeScanner is scan destination of time.Time field which set the field to zero time when the column is NULL
+type nullTimeScanner struct {
+	t *time.Time
+}
+
+// Scan implements sql.Scanner interface
+func (s nullTimeScanner) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*s.t = time.Time{}
+	case time.Time:
+		*s.t = v
+	default:
+		return fmt.Errorf("Error: unsupported scan, storing driver.Value type %T into type time.Time", src)
+	}
+
+	return nil
+}
+
+// Get scan destination of the field, NULL is scanned to zero value of time.Time field instead of returning error
+func (f *Field) scanPtr() interface{} {
+	if f.Value.Type() == timeType {
+		return nullTimeScanner{f.Value.Addr().Interface().(*time.Time)}
+	}
+
+	return f.Value.Addr().Interface()
 }
@@ -1,8 +1,10 @@
 package model_test
 
 import (
+	"database/sql"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/nandaryanizar/fury/model"
 )
@@ -59,6 +61,20 @@ func TestCheckIfZeroValue(t *testing.T) {
 		{1, false},
 		{true, false},
 		{"", true},
+		{[]byte(nil), true},
+		{[]byte{1}, false},
+		{map[string]int(nil), true},
+		{map[string]int{}, false},
+		{(*int)(nil), true},
+		{new(int), false},
+		{[2]int{}, true},
+		{time.Time{}, true},
+		{time.Now(), false},
+		{sql.NullString{}, true},
+		{sql.NullString{String: "Test"}, true},
+		{sql.NullString{String: "Test", Valid: true}, false},
+		{&sql.NullInt64{}, true},
+		{&sql.NullInt64{Valid: true}, false},
 	}
 
 	for _, tc := range cases {
@@ -69,3 +85,37 @@ func TestCheckIfZeroValue(t *testing.T) {
 		}
 	}
 }
+
+type NullableAccount struct {
+	UserID    int
+	LastLogin time.Time
+	Nickname  sql.NullString
+}
+
+func TestScanNullValue(t *testing.T) {
+	have := &NullableAccount{UserID: 1, LastLogin: time.Now(), Nickname: sql.NullString{String: "Test", Valid: true}}
+	_, m, err := model.NewModels(have)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scanner := m.GetScanPtrByColumnNames([]string{"lastlogin", "nickname"})
+	for _, s := range scanner {
+		if err := s.(sql.Scanner).Scan(nil); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if !have.LastLogin.IsZero() || have.Nickname.Valid {
+		t.Errorf("Error: expected zero values, found %v and %v", have.LastLogin, have.Nickname)
+	}
+
+	now := time.Now()
+	if err := scanner[0].(sql.Scanner).Scan(now); err != nil || !have.LastLogin.Equal(now) {
+		t.Errorf("Error: expected %v, found %v", now, have.LastLogin)
+	}
+
+	if err := scanner[0].(sql.Scanner).Scan("Test"); err == nil {
+		t.Error("Expected error found nil")
+	}
+}
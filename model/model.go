@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 )
 
 // Model struct
@@ -15,6 +16,14 @@ type Model struct {
 	PrimaryKeys []*Field
 	Type        reflect.Type
 	ScanAddr    interface{}
+	StructPtr   interface{}
+	Snapshot    *Snapshot
+	Version     *Field
+	SoftDelete  *Field
+	CreatedAt   *Field
+	UpdatedAt   *Field
+
+	nestedScans []*nestedScan
 }
 
 // GetColumnNamesAndValues return names and values as slice
@@ -27,26 +36,297 @@ func (m *Model) GetColumnNamesAndValues(includeAutoInc bool) ([]string, []interf
 			continue
 		}
 
-		cols = append(cols, strings.ToLower(f.Properties.Name))
-		args = append(args, f.Value.Interface())
+		cols = append(cols, f.ColumnName())
+		args = append(args, BindValue(f.Value))
 	}
 
 	return cols, args
 }
 
+// GetUpdateColumnNamesAndValues return names and values of the columns to be updated
+//	Non-empty columns restrict the result to the specified columns, omit exclude the specified columns,
+//	and omitempty tagged field with zero value or unchanged field of tracked model is skipped unless it is specified in columns.
+//	Version and updated_at field are always skipped as they are set by the query itself, created_at field is skipped unless specified in columns
+func (m *Model) GetUpdateColumnNamesAndValues(columns, omit []string) ([]string, []interface{}, error) {
+	for _, col := range append(append([]string{}, columns...), omit...) {
+		if _, ok := m.Fields[col]; !ok {
+			return nil, nil, fmt.Errorf("Error: column %s has no matching field in %s", col, m.Name)
+		}
+	}
+
+	cols := []string{}
+	args := []interface{}{}
+
+	for _, f := range m.FieldSlice {
+		name := f.ColumnName()
+		if f.IsIgnored || f.IsVersion || f.IsUpdatedAt || (f.IsPrimaryKey && f.CheckIfZeroValue()) || containsString(omit, name) {
+			continue
+		}
+
+		if f.IsCreatedAt && !containsString(columns, name) {
+			continue
+		}
+
+		if len(columns) > 0 && !containsString(columns, name) {
+			continue
+		}
+
+		if len(columns) == 0 && f.IsOmitEmpty && f.CheckIfZeroValue() {
+			continue
+		}
+
+		if len(columns) == 0 && !m.isFieldChanged(f) {
+			continue
+		}
+
+		cols = append(cols, name)
+		args = append(args, BindValue(f.Value))
+	}
+
+	return cols, args, nil
+}
+
+func containsString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetVersion return current value of the version field
+func (m *Model) GetVersion() (int64, error) {
+	if m.Version == nil {
+		return 0, fmt.Errorf("Error: %s has no version field", m.Name)
+	}
+
+	switch m.Version.Value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return m.Version.Value.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(m.Version.Value.Uint()), nil
+	}
+
+	return 0, fmt.Errorf("Error: version field must be integer, found %v", m.Version.Value.Kind())
+}
+
+// SetVersion set value of the version field
+func (m *Model) SetVersion(version int64) error {
+	if _, err := m.GetVersion(); err != nil {
+		return err
+	}
+
+	if m.Version.Value.Kind() >= reflect.Uint && m.Version.Value.Kind() <= reflect.Uint64 {
+		m.Version.Value.SetUint(uint64(version))
+		return nil
+	}
+
+	m.Version.Value.SetInt(version)
+	return nil
+}
+
+// SetSoftDeleted set value of the soft delete field to the deletion time
+func (m *Model) SetSoftDeleted(deletedAt time.Time) error {
+	if m.SoftDelete == nil {
+		return fmt.Errorf("Error: %s has no soft delete field", m.Name)
+	}
+
+	return m.SoftDelete.SetTime(deletedAt)
+}
+
+// SetCreatedTimestamps set created_at and updated_at field with zero value to the creation time
+func (m *Model) SetCreatedTimestamps(now time.Time) error {
+	for _, f := range []*Field{m.CreatedAt, m.UpdatedAt} {
+		if f == nil || !f.CheckIfZeroValue() {
+			continue
+		}
+
+		if err := f.SetTime(now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // GetScanPtrByColumnNames return scanner pointers ordered as specifed in the input slice.
+//	Column without matching field is scanned to discard sink so the pointers stay aligned with the columns.
+//	Column of nested pointer to struct is scanned to temporary holder, call FinishScan after scanning to assign it
 func (m *Model) GetScanPtrByColumnNames(columns []string) []interface{} {
-	var pointers []interface{}
+	pointers := make([]interface{}, 0, len(columns))
+	m.nestedScans = nil
 
 	for _, col := range columns {
-		if f, ok := m.Fields[col]; ok {
-			pointers = append(pointers, f.Value.Addr().Interface())
+		if ptr := m.nestedPtrScanPtr(col); ptr != nil {
+			pointers = append(pointers, ptr)
+			continue
+		}
+
+		if f := m.lookupField(col, true); f != nil {
+			pointers = append(pointers, f.scanPtr())
+			continue
 		}
+
+		pointers = append(pointers, discardScanner{})
 	}
 
 	return pointers
 }
 
+// nestedScan struct store the temporary holders of the columns of nested pointer to struct field
+type nestedScan struct {
+	field   reflect.Value
+	ptr     reflect.Value
+	model   *Model
+	targets []reflect.Value
+	holders []reflect.Value
+}
+
+// Get holder of column of nested pointer to struct (e.g. "last.orderid" of Last *Order field), return nil for other column
+//	The column is scanned to pointer of the field type, so NULL column of unmatched LEFT JOIN does not return error
+func (m *Model) nestedPtrScanPtr(col string) interface{} {
+	parts := strings.SplitN(col, ".", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+
+	f, ok := m.Fields[parts[0]]
+	if !ok || f.Value.Kind() != reflect.Ptr || f.Value.Type().Elem().Kind() != reflect.Struct || !f.Value.CanSet() {
+		return nil
+	}
+
+	var n *nestedScan
+	for _, ns := range m.nestedScans {
+		if ns.field == f.Value {
+			n = ns
+		}
+	}
+
+	if n == nil {
+		ptr := f.Value
+		if ptr.IsNil() {
+			ptr = reflect.New(f.Value.Type().Elem())
+		}
+
+		nested, err := newSingleModel(ptr.Elem(), ptr.Elem().Type(), nil)
+		if err != nil {
+			return nil
+		}
+
+		n = &nestedScan{field: f.Value, ptr: ptr, model: nested}
+		m.nestedScans = append(m.nestedScans, n)
+	}
+
+	target := n.model.lookupField(parts[1], true)
+	if target == nil {
+		return nil
+	}
+
+	holder := reflect.New(reflect.PtrTo(target.Value.Type()))
+	n.targets = append(n.targets, target.Value)
+	n.holders = append(n.holders, holder)
+
+	return holder.Interface()
+}
+
+// FinishScan assign the scanned holders of nested pointer to struct fields
+//	The nested pointer is set to nil if all of its columns are NULL, otherwise NULL column is set to zero value
+func (m *Model) FinishScan() {
+	for _, n := range m.nestedScans {
+		isNull := true
+		for _, holder := range n.holders {
+			if !holder.Elem().IsNil() {
+				isNull = false
+			}
+		}
+
+		if isNull {
+			n.field.Set(reflect.Zero(n.field.Type()))
+			continue
+		}
+
+		for i, holder := range n.holders {
+			if holder.Elem().IsNil() {
+				n.targets[i].Set(reflect.Zero(n.targets[i].Type()))
+				continue
+			}
+
+			n.targets[i].Set(holder.Elem().Elem())
+		}
+		n.field.Set(n.ptr)
+	}
+
+	m.nestedScans = nil
+}
+
+// GetUnknownColumns return column names which do not have matching field in the model
+func (m *Model) GetUnknownColumns(columns []string) []string {
+	unknown := []string{}
+
+	for _, col := range columns {
+		if f := m.lookupField(col, false); f == nil {
+			unknown = append(unknown, col)
+		}
+	}
+
+	return unknown
+}
+
+// Find field by column name, column name with dot (e.g. "order.total") is looked up in the nested struct field
+//	When allocate is true, nil pointer to nested struct is allocated so its field can be scanned
+func (m *Model) lookupField(col string, allocate bool) *Field {
+	if f, ok := m.Fields[col]; ok {
+		return f
+	}
+
+	parts := strings.SplitN(col, ".", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+
+	f, ok := m.Fields[parts[0]]
+	if !ok {
+		return nil
+	}
+
+	val := f.Value
+	if val.Kind() == reflect.Ptr {
+		if val.Type().Elem().Kind() != reflect.Struct {
+			return nil
+		}
+
+		if val.IsNil() {
+			if !allocate || !val.CanSet() {
+				val = reflect.New(val.Type().Elem())
+			} else {
+				val.Set(reflect.New(val.Type().Elem()))
+			}
+		}
+		val = val.Elem()
+	}
+
+	if val.Kind() != reflect.Struct {
+		return nil
+	}
+
+	nested, err := newSingleModel(val, val.Type(), nil)
+	if err != nil {
+		return nil
+	}
+
+	return nested.lookupField(parts[1], allocate)
+}
+
+// discardScanner is scan destination that ignore the scanned value
+type discardScanner struct{}
+
+// Scan implements sql.Scanner interface
+func (discardScanner) Scan(src interface{}) error {
+	return nil
+}
+
 // NewModels creates new Model literal
 //  Return slice of pointer to models, first pointer to model, and error
 //  If slice of pointer to models is empty then the second parameter return newly created pointer to model.
@@ -132,35 +412,24 @@ func newSingleModel(structVal reflect.Value, modelType reflect.Type, modelInterf
 		return nil, fmt.Errorf("Error: expected struct, found %v", structVal.Kind())
 	}
 
+	s := getSchema(structVal.Type())
+	if s.err != nil {
+		return nil, s.err
+	}
+
 	// Create model literal
 	m := &Model{
-		Name:     strings.ToLower(structVal.Type().Name()),
-		Fields:   make(map[string]*Field),
+		Name:     s.name,
 		Type:     modelType,
 		ScanAddr: modelInterface,
 	}
 
-	// Iterate through struct fields
-	for i := 0; i < structVal.NumField(); i++ {
-		field := structVal.Field(i)
-
-		if !field.IsValid() {
-			continue
-		}
-
-		fieldProperties := structVal.Type().Field(i)
-		furyField := NewField(fieldProperties, field)
-		fieldPropertiesName := strings.ToLower(fieldProperties.Name)
-
-		if _, ok := m.Fields[fieldPropertiesName]; !ok {
-			m.Fields[fieldPropertiesName] = furyField
-			m.FieldSlice = append(m.FieldSlice, furyField)
-		}
-
-		if furyField.IsPrimaryKey {
-			m.PrimaryKeys = append(m.PrimaryKeys, furyField)
-		}
+	// Pointer to the struct itself, ScanAddr may be the whole slice
+	if structVal.CanAddr() {
+		m.StructPtr = structVal.Addr().Interface()
 	}
 
+	s.bind(structVal, m)
+
 	return m, nil
 }
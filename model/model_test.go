@@ -1,6 +1,7 @@
 package model_test
 
 import (
+	"database/sql/driver"
 	"reflect"
 	"testing"
 	"time"
@@ -144,6 +145,7 @@ func TestNewModelStruct(t *testing.T) {
 
 		tc.want[0].PrimaryKeys = append(tc.want[0].PrimaryKeys, m.Fields["userid"])
 		tc.want[0].ScanAddr = tc.have
+		tc.want[0].StructPtr = tc.have
 		tc.want[0].Type = reflect.ValueOf(tc.have).Type()
 
 		if !reflect.DeepEqual(tc.want, models) {
@@ -185,6 +187,7 @@ func TestNewModelSlice(t *testing.T) {
 
 		tc.want[0].PrimaryKeys = append(tc.want[0].PrimaryKeys, m.Fields["userid"])
 		tc.want[0].ScanAddr = tc.have
+		tc.want[0].StructPtr = reflect.ValueOf(tc.have).Elem().Index(0).Interface()
 
 		tc.want[0].Type = reflect.ValueOf(tc.have).Elem().Index(0).Type()
 
@@ -222,6 +225,7 @@ func TestNewModelEmptySlice(t *testing.T) {
 
 		tc.want[0].PrimaryKeys = append(tc.want[0].PrimaryKeys, m.Fields["userid"])
 		tc.want[0].ScanAddr = tc.have
+		tc.want[0].StructPtr = reflect.ValueOf(tc.have).Elem().Index(0).Interface()
 
 		tc.want[0].Type = reflect.ValueOf(tc.have).Elem().Type().Elem()
 
@@ -230,3 +234,294 @@ func TestNewModelEmptySlice(t *testing.T) {
 		}
 	}
 }
+
+func TestGetScannerUnknownColumns(t *testing.T) {
+	_, m, err := model.NewModels(&Account{UserID: 123, Counter: 1})
+	if err != nil {
+		t.Error(err)
+	}
+
+	columns := []string{"userid", "email", "counter"}
+
+	scanner := m.GetScanPtrByColumnNames(columns)
+	if len(scanner) != len(columns) {
+		t.Errorf("Error: expected %d scanner, found %d", len(columns), len(scanner))
+	}
+
+	unknown := m.GetUnknownColumns(columns)
+	if !reflect.DeepEqual(unknown, []string{"email"}) {
+		t.Errorf("Error: expected %v, found %v", []string{"email"}, unknown)
+	}
+}
+
+type Profile struct {
+	UserID  int    `fury:"primary_key"`
+	Email   string `fury:"omitempty"`
+	Counter int
+}
+
+func TestGetUpdateColumnNamesAndValues(t *testing.T) {
+	cases := []struct {
+		have     interface{}
+		columns  []string
+		omit     []string
+		wantCols []string
+		wantArgs []interface{}
+	}{
+		{&Profile{UserID: 1}, nil, nil, []string{"userid", "counter"}, []interface{}{1, 0}},
+		{&Profile{Email: "a"}, nil, nil, []string{"email", "counter"}, []interface{}{"a", 0}},
+		{&Profile{UserID: 1}, []string{"email"}, nil, []string{"email"}, []interface{}{""}},
+		{&Profile{Email: "a", Counter: 2}, nil, []string{"email"}, []string{"counter"}, []interface{}{2}},
+	}
+
+	for _, tc := range cases {
+		_, m, err := model.NewModels(tc.have)
+		if err != nil {
+			t.Error(err)
+		}
+
+		cols, args, err := m.GetUpdateColumnNamesAndValues(tc.columns, tc.omit)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if !reflect.DeepEqual(cols, tc.wantCols) || !reflect.DeepEqual(args, tc.wantArgs) {
+			t.Errorf("Error: expected %v and %v, found %v and %v", tc.wantCols, tc.wantArgs, cols, args)
+		}
+	}
+
+	_, m, _ := model.NewModels(&Profile{UserID: 1})
+	if _, _, err := m.GetUpdateColumnNamesAndValues([]string{"unknown"}, nil); err == nil {
+		t.Error("Expected error found nil")
+	}
+}
+
+// nullableName implements driver.Valuer with pointer receiver, empty name is written as NULL
+type nullableName string
+
+func (n *nullableName) Value() (driver.Value, error) {
+	if *n == "" {
+		return nil, nil
+	}
+	return string(*n), nil
+}
+
+type NamedAccount struct {
+	UserID int `fury:"primary_key"`
+	Name   nullableName
+}
+
+func TestGetColumnNamesAndValuesPointerValuer(t *testing.T) {
+	cases := []struct {
+		have interface{}
+		want driver.Value
+	}{
+		{&NamedAccount{UserID: 1, Name: "a"}, "a"},
+		{&NamedAccount{UserID: 1}, nil},
+	}
+
+	for _, tc := range cases {
+		_, m, err := model.NewModels(tc.have)
+		if err != nil {
+			t.Error(err)
+		}
+
+		_, insertArgs := m.GetColumnNamesAndValues(false)
+		_, updateArgs, err := m.GetUpdateColumnNamesAndValues(nil, nil)
+		if err != nil {
+			t.Error(err)
+		}
+
+		// Value is bound through its pointer, so the driver write it as returned by the Value method
+		for _, args := range [][]interface{}{insertArgs, updateArgs} {
+			valuer, ok := args[len(args)-1].(driver.Valuer)
+			if !ok {
+				t.Errorf("Error: expected driver.Valuer, found %T", args[len(args)-1])
+				continue
+			}
+
+			if val, _ := valuer.Value(); val != tc.want {
+				t.Errorf("Error: expected %v, found %v", tc.want, val)
+			}
+		}
+	}
+}
+
+type VersionedAccount struct {
+	UserID  int  `fury:"primary_key"`
+	Version uint `fury:"version"`
+}
+
+type InvalidVersionAccount struct {
+	UserID  int    `fury:"primary_key"`
+	Version string `fury:"version"`
+}
+
+func TestVersionField(t *testing.T) {
+	acc := &VersionedAccount{UserID: 1, Version: 2}
+	_, m, err := model.NewModels(acc)
+	if err != nil {
+		t.Error(err)
+	}
+
+	cols, _, _ := m.GetUpdateColumnNamesAndValues(nil, nil)
+	if !reflect.DeepEqual(cols, []string{"userid"}) {
+		t.Errorf("Error: expected %v, found %v", []string{"userid"}, cols)
+	}
+
+	if err := m.SetVersion(3); err != nil {
+		t.Error(err)
+	}
+
+	version, err := m.GetVersion()
+	if err != nil || version != 3 || acc.Version != 3 {
+		t.Errorf("Error: expected %v, found %v", 3, version)
+	}
+
+	_, m, _ = model.NewModels(&InvalidVersionAccount{})
+	if _, err := m.GetVersion(); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	_, m, _ = model.NewModels(&Account{})
+	if err := m.SetVersion(1); err == nil {
+		t.Error("Expected error found nil")
+	}
+}
+
+type InvalidSoftDeleteAccount struct {
+	UserID    int  `fury:"primary_key"`
+	DeletedAt bool `fury:"soft_delete"`
+}
+
+func TestSoftDeleteField(t *testing.T) {
+	if _, _, err := model.NewModels(&InvalidSoftDeleteAccount{}); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	_, m, _ := model.NewModels(&Account{})
+	if err := m.SetSoftDeleted(time.Now()); err == nil {
+		t.Error("Expected error found nil")
+	}
+}
+
+type Order struct {
+	OrderID int
+	Total   int
+}
+
+type AccountOrder struct {
+	UserID int
+	Order  Order
+	Last   *Order
+}
+
+func TestGetScannerNestedStruct(t *testing.T) {
+	have := &AccountOrder{}
+	_, m, err := model.NewModels(have)
+	if err != nil {
+		t.Error(err)
+	}
+
+	columns := []string{"userid", "order.total", "last.orderid", "order.unknown"}
+
+	if unknown := m.GetUnknownColumns(columns); !reflect.DeepEqual(unknown, []string{"order.unknown"}) {
+		t.Errorf("Error: expected %v, found %v", []string{"order.unknown"}, unknown)
+	}
+
+	if have.Last != nil {
+		t.Error("Error: nested pointer should not be allocated when checking unknown columns")
+	}
+
+	scanner := m.GetScanPtrByColumnNames(columns)
+	want := []interface{}{&have.UserID, &have.Order.Total}
+	if !reflect.DeepEqual(scanner[:2], want) {
+		t.Errorf("Error: expected %v, found %v", want, scanner[:2])
+	}
+
+	// Column of nested pointer is scanned to holder and assigned by FinishScan
+	orderID := 5
+	*scanner[2].(**int) = &orderID
+	m.FinishScan()
+	if have.Last == nil || have.Last.OrderID != 5 {
+		t.Fatalf("Error: expected %v, found %v", orderID, have.Last)
+	}
+
+	// Nested pointer is set to nil if all of its columns are NULL, e.g. unmatched LEFT JOIN
+	m.GetScanPtrByColumnNames(columns)
+	m.FinishScan()
+	if have.Last != nil {
+		t.Errorf("Error: expected nil, found %v", have.Last)
+	}
+}
+
+type BaseModel struct {
+	ID        int `fury:"primary_key,auto_increment"`
+	CreatedAt time.Time
+}
+
+type Address struct {
+	Street string
+	City   string
+}
+
+type Customer struct {
+	BaseModel
+	*Address
+	Name     string
+	Shipping Address  `fury:"embedded,prefix:ship_"`
+	Billing  *Address `fury:"embedded,prefix:Bill_"`
+}
+
+type ShadowedCustomer struct {
+	BaseModel
+	ID   int
+	Name string
+}
+
+type InvalidEmbeddedCustomer struct {
+	ID   int
+	Name string `fury:"embedded"`
+}
+
+func TestEmbeddedStruct(t *testing.T) {
+	have := &Customer{BaseModel: BaseModel{ID: 1}, Name: "Test", Shipping: Address{Street: "Main"}}
+	_, m, err := model.NewModels(have)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"id", "createdat", "street", "city", "name", "ship_street", "ship_city", "bill_street", "bill_city"}
+	cols, _ := m.GetColumnNamesAndValues(true)
+	if !reflect.DeepEqual(want, cols) {
+		t.Errorf("Error: expected %v, found %v", want, cols)
+	}
+
+	if len(m.PrimaryKeys) != 1 || m.PrimaryKeys[0].ColumnName() != "id" {
+		t.Errorf("Error: expected %v, found %v", "id", m.PrimaryKeys)
+	}
+
+	if have.Address == nil || have.Billing == nil {
+		t.Fatal("Error: embedded pointer should be allocated")
+	}
+
+	scanner := m.GetScanPtrByColumnNames([]string{"id", "street", "ship_street", "bill_city"})
+	wantScanner := []interface{}{&have.ID, &have.Address.Street, &have.Shipping.Street, &have.Billing.City}
+	if !reflect.DeepEqual(wantScanner, scanner) {
+		t.Errorf("Error: expected %v, found %v", wantScanner, scanner)
+	}
+
+	shadowed := &ShadowedCustomer{BaseModel: BaseModel{ID: 1}, ID: 2}
+	_, m, err = model.NewModels(shadowed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(m.PrimaryKeys) != 0 || m.Fields["id"].Value.Interface() != 2 {
+		t.Errorf("Error: expected %v, found %v", 2, m.Fields["id"].Value.Interface())
+	}
+
+	if _, _, err := model.NewModels(&InvalidEmbeddedCustomer{}); err == nil {
+		t.Error("Expected error found nil")
+	}
+}
@@ -0,0 +1,218 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// schema struct store metadata of struct type parsed once and shared by every model of the type
+type schema struct {
+	name          string
+	fields        []schemaField
+	snapshotIndex []int
+	err           error
+}
+
+// schemaField struct store field template and index path of the field in the struct
+// 	Field without column is not registered to Fields, e.g. duplicate column name, but it may still be primary key.
+//	Field of embedded struct has depth more than zero, and like Go promoted field, the shallowest field of the same column is registered
+type schemaField struct {
+	field      Field
+	index      []int
+	depth      int
+	column     string
+	registered bool
+}
+
+// Cache of schema per struct type, so creating model of the same type only bind the field values
+var schemaCache = struct {
+	sync.RWMutex
+	schemas map[reflect.Type]*schema
+}{schemas: make(map[reflect.Type]*schema)}
+
+// Get schema of struct type from cache, or parse and store it if not exists
+func getSchema(structType reflect.Type) *schema {
+	schemaCache.RLock()
+	s, ok := schemaCache.schemas[structType]
+	schemaCache.RUnlock()
+	if ok {
+		return s
+	}
+
+	s = newSchema(structType)
+
+	schemaCache.Lock()
+	schemaCache.schemas[structType] = s
+	schemaCache.Unlock()
+
+	return s
+}
+
+func newSchema(structType reflect.Type) *schema {
+	s := &schema{
+		name: strings.ToLower(structType.Name()),
+	}
+
+	if s.err = s.addFields(structType, nil, "", 0, []reflect.Type{structType}); s.err != nil {
+		return s
+	}
+
+	// Register the shallowest field of each column, the first one wins if the depth is equal
+	registered := map[string]int{}
+	for i, sf := range s.fields {
+		if j, ok := registered[sf.column]; !ok || sf.depth < s.fields[j].depth {
+			registered[sf.column] = i
+		}
+	}
+
+	for _, i := range registered {
+		s.fields[i].registered = true
+	}
+
+	return s
+}
+
+// Add fields of the struct type to the schema, anonymous embedded struct and named struct field with embedded tag are flattened
+func (s *schema) addFields(structType reflect.Type, index []int, prefix string, depth int, visited []reflect.Type) error {
+	for i := 0; i < structType.NumField(); i++ {
+		fieldProperties := structType.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+
+		if fieldProperties.Anonymous && fieldProperties.Type == snapshotType {
+			if s.snapshotIndex == nil || len(fieldIndex) < len(s.snapshotIndex) {
+				s.snapshotIndex = fieldIndex
+			}
+			continue
+		}
+
+		furyField := NewField(fieldProperties, reflect.Value{})
+		if err := furyField.TagError(); err != nil {
+			return err
+		}
+
+		if embeddedType, ok := embeddedStructType(fieldProperties, furyField); ok {
+			for _, t := range visited {
+				if t == embeddedType {
+					return fmt.Errorf("Error: recursive embedded struct %v in %s", embeddedType, s.name)
+				}
+			}
+
+			if err := s.addFields(embeddedType, fieldIndex, prefix+furyField.Prefix, depth+1, append(visited, embeddedType)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if furyField.IsEmbedded {
+			return fmt.Errorf("Error: embedded field %s must be struct or pointer to struct, found %v", fieldProperties.Name, fieldProperties.Type)
+		}
+
+		if (furyField.IsSoftDelete || furyField.IsCreatedAt || furyField.IsUpdatedAt) && fieldProperties.Type != timeType && fieldProperties.Type != reflect.PtrTo(timeType) {
+			return fmt.Errorf("Error: soft_delete, created_at and updated_at field must be time.Time or *time.Time, found %v", fieldProperties.Type)
+		}
+
+		column := prefix + strings.ToLower(fieldProperties.Name)
+		if prefix != "" {
+			furyField.column = column
+		}
+
+		s.fields = append(s.fields, schemaField{
+			field:  *furyField,
+			index:  fieldIndex,
+			depth:  depth,
+			column: column,
+		})
+	}
+
+	return nil
+}
+
+// Return the struct type if the field is anonymous embedded struct or tagged as embedded, time.Time is kept as single field
+func embeddedStructType(prop reflect.StructField, f *Field) (reflect.Type, bool) {
+	if !prop.Anonymous && !f.IsEmbedded {
+		return nil, false
+	}
+
+	t := prop.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct || t == timeType {
+		return nil, false
+	}
+
+	return t, true
+}
+
+// Get field of the struct value by index path, nil pointer to embedded struct is allocated
+//	Unsettable nil pointer, e.g. pointer to unexported embedded struct, is replaced with detached value
+func fieldByIndex(structVal reflect.Value, index []int) reflect.Value {
+	val := structVal
+	for i, idx := range index {
+		if i > 0 && val.Kind() == reflect.Ptr {
+			if val.IsNil() {
+				if !val.CanSet() {
+					val = reflect.New(val.Type().Elem())
+				} else {
+					val.Set(reflect.New(val.Type().Elem()))
+				}
+			}
+			val = val.Elem()
+		}
+
+		val = val.Field(idx)
+	}
+
+	return val
+}
+
+// Create model of the struct value by binding the field values to the schema
+func (s *schema) bind(structVal reflect.Value, m *Model) {
+	m.Fields = make(map[string]*Field, len(s.fields))
+	m.FieldSlice = make([]*Field, 0, len(s.fields))
+
+	// Allocate all fields at once instead of one by one
+	fields := make([]Field, len(s.fields))
+	for i, sf := range s.fields {
+		fields[i] = sf.field
+		furyField := &fields[i]
+		furyField.Value = fieldByIndex(structVal, sf.index)
+
+		if sf.registered {
+			m.Fields[sf.column] = furyField
+			m.FieldSlice = append(m.FieldSlice, furyField)
+		} else if sf.depth > 0 {
+			// Shadowed field of embedded struct is not promoted
+			continue
+		}
+
+		if furyField.IsPrimaryKey {
+			m.PrimaryKeys = append(m.PrimaryKeys, furyField)
+		}
+
+		if furyField.IsVersion && m.Version == nil {
+			m.Version = furyField
+		}
+
+		if furyField.IsSoftDelete && m.SoftDelete == nil {
+			m.SoftDelete = furyField
+		}
+
+		if furyField.IsCreatedAt && m.CreatedAt == nil {
+			m.CreatedAt = furyField
+		}
+
+		if furyField.IsUpdatedAt && m.UpdatedAt == nil {
+			m.UpdatedAt = furyField
+		}
+	}
+
+	if s.snapshotIndex != nil {
+		if snapshot := fieldByIndex(structVal, s.snapshotIndex); snapshot.CanAddr() {
+			m.Snapshot = snapshot.Addr().Interface().(*Snapshot)
+		}
+	}
+}
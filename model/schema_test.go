@@ -0,0 +1,107 @@
+package model_test
+
+import (
+	"database/sql"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nandaryanizar/fury/model"
+)
+
+type SchemaAccount struct {
+	UserID    int    `fury:"primary_key,auto_increment"`
+	Username  string `fury:"min_len:3,max_len:50"`
+	Password  string
+	Email     string `fury:"omitempty"`
+	CreatedOn time.Time
+	LastLogin time.Time
+}
+
+func TestSchemaCacheConcurrent(t *testing.T) {
+	model.ResetSchemaCache()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+
+			acc := &SchemaAccount{UserID: id}
+			_, m, err := model.NewModels(acc)
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			if m.Fields["userid"].Value.Interface() != id || len(m.FieldSlice) != 6 || len(m.PrimaryKeys) != 1 {
+				t.Errorf("Error: expected model bound to account %d, found %v", id, m.Fields["userid"].Value)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestSchemaCacheBindValues(t *testing.T) {
+	first := &SchemaAccount{UserID: 1}
+	second := &SchemaAccount{UserID: 2}
+
+	_, m1, _ := model.NewModels(first)
+	_, m2, _ := model.NewModels(second)
+
+	m2.Fields["username"].Value.SetString("second")
+	if first.Username != "" || second.Username != "second" {
+		t.Errorf("Error: expected models of the same type bound to different struct, found %v and %v", first, second)
+	}
+
+	if !reflect.DeepEqual(m1.Fields["userid"].Properties, m2.Fields["userid"].Properties) {
+		t.Error("Error: expected models of the same type share field properties")
+	}
+}
+
+const benchmarkRows = 100000
+
+var benchmarkColumns = []string{"userid", "username", "password", "email", "createdon", "lastlogin"}
+
+// Simulate scanning query result, each row create new model and bind scan pointers as DB.Find does
+func scanRows(b *testing.B, resetCache bool) {
+	accType := reflect.TypeOf(SchemaAccount{})
+	now := time.Now()
+
+	for n := 0; n < b.N; n++ {
+		for i := 0; i < benchmarkRows; i++ {
+			if resetCache {
+				model.ResetSchemaCache()
+			}
+
+			_, m, err := model.NewModels(reflect.New(accType).Interface())
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			pointers := m.GetScanPtrByColumnNames(benchmarkColumns)
+			*pointers[0].(*int) = i
+			*pointers[1].(*string) = "username"
+			if err := pointers[4].(sql.Scanner).Scan(now); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkScanRows100k(b *testing.B) {
+	b.ReportAllocs()
+	scanRows(b, false)
+}
+
+func BenchmarkScanRows100kWithoutCache(b *testing.B) {
+	b.ReportAllocs()
+	scanRows(b, true)
+}
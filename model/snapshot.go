@@ -0,0 +1,114 @@
+package model
+
+import (
+	"reflect"
+)
+
+// Snapshot struct store the field values of the model as loaded from database
+//	Embed it to the model struct to enable dirty tracking, so UPDATE query only contains the modified columns
+type Snapshot struct {
+	values map[string]interface{}
+}
+
+var snapshotType = reflect.TypeOf(Snapshot{})
+
+// TakeSnapshot record current field values of the model, it does nothing if the model does not embed Snapshot
+//	Non-empty columns only record the specified columns and keep the others as is
+func (m *Model) TakeSnapshot(columns ...string) {
+	if m.Snapshot == nil {
+		return
+	}
+
+	if len(columns) == 0 || m.Snapshot.values == nil {
+		m.Snapshot.values = make(map[string]interface{})
+	}
+
+	for _, f := range m.FieldSlice {
+		name := f.ColumnName()
+		if f.IsIgnored || !f.Value.CanInterface() || (len(columns) > 0 && !containsString(columns, name)) {
+			continue
+		}
+
+		m.Snapshot.values[name] = snapshotValue(f.Value)
+	}
+}
+
+// IsTracked return true if the model embed Snapshot and its values has been recorded
+func (m *Model) IsTracked() bool {
+	return m.Snapshot != nil && m.Snapshot.values != nil
+}
+
+// IsDirty return true if any field value differ from the snapshot
+//	Model which is not tracked is always considered dirty
+func (m *Model) IsDirty() bool {
+	if !m.IsTracked() {
+		return true
+	}
+
+	for _, f := range m.FieldSlice {
+		if !f.IsVersion && !f.IsUpdatedAt && m.isFieldChanged(f) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *Model) isFieldChanged(f *Field) bool {
+	if !m.IsTracked() {
+		return true
+	}
+
+	if f.IsIgnored || !f.Value.CanInterface() {
+		return false
+	}
+
+	val, ok := m.Snapshot.values[f.ColumnName()]
+	if !ok {
+		return true
+	}
+
+	return !reflect.DeepEqual(val, snapshotValue(f.Value))
+}
+
+// Copy value of the field, so modification through the same pointer, slice or map is detected
+func snapshotValue(val reflect.Value) interface{} {
+	return deepCopy(val).Interface()
+}
+
+// deepCopy return a copy of the value with the same type, the value pointed by pointer and the elements of slice and map are copied recursively
+//	Comparing the copy with reflect.DeepEqual still differ nil pointer from pointer to zero value
+func deepCopy(val reflect.Value) reflect.Value {
+	switch val.Kind() {
+	case reflect.Ptr:
+		if val.IsNil() {
+			return val
+		}
+
+		copied := reflect.New(val.Type().Elem())
+		copied.Elem().Set(deepCopy(val.Elem()))
+		return copied
+	case reflect.Slice:
+		if val.IsNil() {
+			return val
+		}
+
+		copied := reflect.MakeSlice(val.Type(), val.Len(), val.Len())
+		for i := 0; i < val.Len(); i++ {
+			copied.Index(i).Set(deepCopy(val.Index(i)))
+		}
+		return copied
+	case reflect.Map:
+		if val.IsNil() {
+			return val
+		}
+
+		copied := reflect.MakeMapWithSize(val.Type(), val.Len())
+		for _, key := range val.MapKeys() {
+			copied.SetMapIndex(key, deepCopy(val.MapIndex(key)))
+		}
+		return copied
+	}
+
+	return val
+}
@@ -0,0 +1,79 @@
+package model_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nandaryanizar/fury/model"
+)
+
+type TrackedAccount struct {
+	model.Snapshot
+	UserID  int `fury:"primary_key"`
+	Email   string
+	Counter *int
+	Tags    []string
+	Labels  map[string][]string
+	Parents [][]int
+}
+
+func TestSnapshotDirtyTracking(t *testing.T) {
+	counter := 1
+	acc := &TrackedAccount{UserID: 1, Email: "some@test.com", Counter: &counter, Tags: []string{"a"}, Labels: map[string][]string{"a": {"b"}}, Parents: [][]int{{1}}}
+
+	_, m, err := model.NewModels(acc)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if _, ok := m.Fields["snapshot"]; ok {
+		t.Error("Error: embedded snapshot should not be a field")
+	}
+
+	if m.IsTracked() || !m.IsDirty() {
+		t.Error("Error: model without snapshot should be dirty")
+	}
+
+	m.TakeSnapshot()
+	if !m.IsTracked() || m.IsDirty() {
+		t.Error("Error: model should not be dirty after snapshot")
+	}
+
+	cases := []struct {
+		modify   func()
+		wantCols []string
+	}{
+		{func() { acc.Email = "other@test.com" }, []string{"email"}},
+		{func() { *acc.Counter = 2 }, []string{"counter"}},
+		{func() { acc.Tags[0] = "b" }, []string{"tags"}},
+		{func() { acc.Counter = nil }, []string{"counter"}},
+		{func() { acc.Labels["a"] = []string{"c"} }, []string{"labels"}},
+		{func() { acc.Labels["a"][0] = "d" }, []string{"labels"}},
+		{func() { delete(acc.Labels, "a") }, []string{"labels"}},
+		{func() { acc.Parents[0][0] = 2 }, []string{"parents"}},
+	}
+
+	for _, tc := range cases {
+		tc.modify()
+
+		cols, _, err := m.GetUpdateColumnNamesAndValues(nil, nil)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if !m.IsDirty() || !reflect.DeepEqual(cols, tc.wantCols) {
+			t.Errorf("Error: expected %v, found %v", tc.wantCols, cols)
+		}
+
+		m.TakeSnapshot()
+	}
+
+	acc.Email = "partial@test.com"
+	acc.UserID = 2
+	m.TakeSnapshot("email")
+
+	cols, _, _ := m.GetUpdateColumnNamesAndValues(nil, nil)
+	if !reflect.DeepEqual(cols, []string{"userid"}) {
+		t.Errorf("Error: expected %v, found %v", []string{"userid"}, cols)
+	}
+}
@@ -0,0 +1,231 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldError struct describe validation failure of a single field
+type FieldError struct {
+	Field   string `json:"field"`
+	Column  string `json:"column"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationError struct contains every field validation failure of a model
+// 	It is returned by Insert and Update before the query is executed
+type ValidationError struct {
+	Model  string       `json:"model"`
+	Errors []FieldError `json:"errors"`
+}
+
+// Error method return all field errors as single message
+func (ve *ValidationError) Error() string {
+	messages := []string{}
+	for _, fe := range ve.Errors {
+		messages = append(messages, fmt.Sprintf("%s %s", fe.Column, fe.Message))
+	}
+
+	return fmt.Sprintf("Error: validation of %s failed: %s", ve.Model, strings.Join(messages, "; "))
+}
+
+// validationRule struct store single validation rule parsed from the tag, e.g. max_len:50
+type validationRule struct {
+	name    string
+	arg     string
+	number  float64
+	pattern *regexp.Regexp
+}
+
+var validationRuleNames = []string{"not_null", "min_len", "max_len", "min", "max", "pattern"}
+
+func isValidationRule(tag string) bool {
+	name := strings.ToLower(strings.SplitN(tag, ":", 2)[0])
+	return containsString(validationRuleNames, name)
+}
+
+// Parse validation rule, invalid rule is stored as tag error and returned when creating the model
+func (f *Field) addValidationRule(tag string) {
+	parts := strings.SplitN(tag, ":", 2)
+	rule := validationRule{name: strings.ToLower(parts[0])}
+	if len(parts) > 1 {
+		rule.arg = parts[1]
+	}
+
+	var err error
+	switch rule.name {
+	case "not_null":
+	case "min_len", "max_len", "min", "max":
+		rule.number, err = strconv.ParseFloat(rule.arg, 64)
+	case "pattern":
+		rule.pattern, err = regexp.Compile(rule.arg)
+	}
+
+	if err != nil {
+		if f.tagErr == nil {
+			f.tagErr = fmt.Errorf("Error: invalid %s rule of field %s: %v", rule.name, f.Properties.Name, err)
+		}
+		return
+	}
+
+	f.rules = append(f.rules, rule)
+}
+
+// TagError return error of invalid tag, e.g. validation rule with invalid argument
+func (f *Field) TagError() error {
+	return f.tagErr
+}
+
+// Validate value against the rules of the field, return error message of the first failed rule
+func (f *Field) validate(val reflect.Value) (string, string) {
+	for _, rule := range f.rules {
+		if rule.name == "not_null" {
+			if isNullValue(val) {
+				return rule.name, "must not be null"
+			}
+			continue
+		}
+
+		// Other rules are not applied to null value
+		if isNullValue(val) {
+			continue
+		}
+
+		// driver.Valuer such as sql.NullString is checked against its database value
+		v := reflect.Indirect(val)
+		if valuer, ok := getValuer(val); ok {
+			dbVal, err := valuer.Value()
+			if err != nil {
+				return rule.name, err.Error()
+			}
+			v = reflect.ValueOf(dbVal)
+		}
+
+		if msg := rule.check(v); msg != "" {
+			return rule.name, msg
+		}
+	}
+
+	return "", ""
+}
+
+// Check value against the rule, return empty string if the value is valid
+func (rule validationRule) check(v reflect.Value) string {
+	switch rule.name {
+	case "min_len", "max_len":
+		length := 0
+		switch v.Kind() {
+		case reflect.String:
+			length = utf8.RuneCountInString(v.String())
+		case reflect.Slice, reflect.Array, reflect.Map:
+			length = v.Len()
+		default:
+			return fmt.Sprintf("has unsupported type %v for %s", v.Type(), rule.name)
+		}
+
+		if rule.name == "min_len" && float64(length) < rule.number {
+			return fmt.Sprintf("must be at least %s characters", rule.arg)
+		}
+
+		if rule.name == "max_len" && float64(length) > rule.number {
+			return fmt.Sprintf("must be at most %s characters", rule.arg)
+		}
+	case "min", "max":
+		var number float64
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			number = float64(v.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			number = float64(v.Uint())
+		case reflect.Float32, reflect.Float64:
+			number = v.Float()
+		default:
+			return fmt.Sprintf("has unsupported type %v for %s", v.Type(), rule.name)
+		}
+
+		if rule.name == "min" && number < rule.number {
+			return fmt.Sprintf("must be at least %s", rule.arg)
+		}
+
+		if rule.name == "max" && number > rule.number {
+			return fmt.Sprintf("must be at most %s", rule.arg)
+		}
+	case "pattern":
+		if v.Kind() != reflect.String {
+			return fmt.Sprintf("has unsupported type %v for %s", v.Type(), rule.name)
+		}
+
+		if !rule.pattern.MatchString(v.String()) {
+			return fmt.Sprintf("must match pattern %s", rule.arg)
+		}
+	}
+
+	return ""
+}
+
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		return v.IsNil()
+	}
+
+	return false
+}
+
+// Validate method check the field values against the validation rules in the tags
+// 	Non-empty columns only validate the specified columns. Return *ValidationError containing every failed field.
+func (m *Model) Validate(columns ...string) error {
+	errs := []FieldError{}
+	for _, f := range m.FieldSlice {
+		name := f.ColumnName()
+		if len(f.rules) == 0 || (len(columns) > 0 && !containsString(columns, name)) {
+			continue
+		}
+
+		if rule, msg := f.validate(f.Value); msg != "" {
+			errs = append(errs, newFieldError(f, rule, msg))
+		}
+	}
+
+	return m.validationError(errs)
+}
+
+// ValidateValues method check the column values, e.g. values of update map, against the validation rules of the matching fields
+// 	Column without matching field is not validated, nil value is validated as null. Return *ValidationError containing every failed column.
+func (m *Model) ValidateValues(values map[string]interface{}) error {
+	errs := []FieldError{}
+	for _, f := range m.FieldSlice {
+		value, ok := values[f.ColumnName()]
+		if len(f.rules) == 0 || !ok {
+			continue
+		}
+
+		if rule, msg := f.validate(reflect.ValueOf(value)); msg != "" {
+			errs = append(errs, newFieldError(f, rule, msg))
+		}
+	}
+
+	return m.validationError(errs)
+}
+
+func newFieldError(f *Field, rule, msg string) FieldError {
+	return FieldError{
+		Field:   f.Properties.Name,
+		Column:  f.ColumnName(),
+		Rule:    rule,
+		Message: msg,
+	}
+}
+
+func (m *Model) validationError(errs []FieldError) error {
+	if len(errs) > 0 {
+		return &ValidationError{Model: m.Name, Errors: errs}
+	}
+
+	return nil
+}
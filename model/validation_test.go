@@ -0,0 +1,153 @@
+package model_test
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/nandaryanizar/fury/model"
+)
+
+type ValidatedAccount struct {
+	UserID   int     `fury:"primary_key"`
+	Username string  `fury:"min_len:3,max_len:10"`
+	Email    *string `fury:"not_null,pattern:^[a-z]+@[a-z]+\\.[a-z]{2,3}$"`
+	Balance  float64 `fury:"min:0,max:1000"`
+	Nickname *string `fury:"max_len:3"`
+}
+
+func TestValidate(t *testing.T) {
+	email := "some@test.com"
+	invalidEmail := "Some@Test"
+	nickname := "abcd"
+
+	cases := []struct {
+		have      *ValidatedAccount
+		columns   []string
+		wantRules []string
+	}{
+		{&ValidatedAccount{Username: "someuser", Email: &email, Balance: 10}, nil, nil},
+		{&ValidatedAccount{Username: "ab", Email: nil, Balance: -1}, nil, []string{"min_len", "not_null", "min"}},
+		{&ValidatedAccount{Username: "someverylonguser", Email: &invalidEmail, Balance: 1001, Nickname: &nickname}, nil, []string{"max_len", "pattern", "max", "max_len"}},
+		{&ValidatedAccount{Username: "ab", Email: nil, Balance: -1}, []string{"balance"}, []string{"min"}},
+	}
+
+	for _, tc := range cases {
+		_, m, err := model.NewModels(tc.have)
+		if err != nil {
+			t.Error(err)
+		}
+
+		err = m.Validate(tc.columns...)
+		if tc.wantRules == nil {
+			if err != nil {
+				t.Errorf("Error: expected nil, found %v", err)
+			}
+			continue
+		}
+
+		validationErr, ok := err.(*model.ValidationError)
+		if !ok {
+			t.Errorf("Error: expected validation error, found %v", err)
+			continue
+		}
+
+		rules := []string{}
+		for _, fe := range validationErr.Errors {
+			rules = append(rules, fe.Rule)
+		}
+
+		if !reflect.DeepEqual(rules, tc.wantRules) {
+			t.Errorf("Error: expected %v, found %v", tc.wantRules, rules)
+		}
+	}
+}
+
+type ValidatedNullAccount struct {
+	UserID   int            `fury:"primary_key"`
+	Referral sql.NullString `fury:"not_null,max_len:3"`
+	Score    sql.NullInt64  `fury:"min:1"`
+}
+
+func TestValidateNullValuer(t *testing.T) {
+	cases := []struct {
+		have      *ValidatedNullAccount
+		wantRules []string
+	}{
+		{&ValidatedNullAccount{Referral: sql.NullString{String: "abc", Valid: true}}, nil},
+		{&ValidatedNullAccount{Referral: sql.NullString{String: "abc"}}, []string{"not_null"}},
+		{&ValidatedNullAccount{Referral: sql.NullString{String: "abcd", Valid: true}, Score: sql.NullInt64{Valid: true}}, []string{"max_len", "min"}},
+	}
+
+	for _, tc := range cases {
+		_, m, err := model.NewModels(tc.have)
+		if err != nil {
+			t.Error(err)
+		}
+
+		rules := []string{}
+		if validationErr, ok := m.Validate().(*model.ValidationError); ok {
+			for _, fe := range validationErr.Errors {
+				rules = append(rules, fe.Rule)
+			}
+		}
+
+		if len(rules) != len(tc.wantRules) || (len(rules) > 0 && !reflect.DeepEqual(rules, tc.wantRules)) {
+			t.Errorf("Error: expected %v, found %v", tc.wantRules, rules)
+		}
+	}
+}
+
+func TestValidateValues(t *testing.T) {
+	_, m, err := model.NewModels(&ValidatedAccount{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		have      map[string]interface{}
+		wantRules []string
+	}{
+		{map[string]interface{}{"username": "someuser", "unknown": 1}, nil},
+		{map[string]interface{}{"username": "ab", "email": nil, "balance": 1001.0}, []string{"min_len", "not_null", "max"}},
+		{map[string]interface{}{"nickname": "abcd"}, []string{"max_len"}},
+	}
+
+	for _, tc := range cases {
+		rules := []string{}
+		if validationErr, ok := m.ValidateValues(tc.have).(*model.ValidationError); ok {
+			for _, fe := range validationErr.Errors {
+				rules = append(rules, fe.Rule)
+			}
+		}
+
+		if len(rules) != len(tc.wantRules) || (len(rules) > 0 && !reflect.DeepEqual(rules, tc.wantRules)) {
+			t.Errorf("Error: expected %v, found %v", tc.wantRules, rules)
+		}
+	}
+}
+
+type InvalidRuleAccount struct {
+	UserID  int     `fury:"primary_key"`
+	Balance float64 `fury:"min:zero"`
+}
+
+type InvalidPatternAccount struct {
+	UserID int    `fury:"primary_key"`
+	Email  string `fury:"pattern:[a-z"`
+}
+
+func TestInvalidValidationRule(t *testing.T) {
+	cases := []struct {
+		have interface{}
+	}{
+		{&InvalidRuleAccount{}},
+		{&InvalidPatternAccount{}},
+	}
+
+	for _, tc := range cases {
+		if _, _, err := model.NewModels(tc.have); err == nil {
+			t.Error("Expected error found nil")
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package fury
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/nandaryanizar/fury/model"
+)
+
+const (
+	cursorNext = "next"
+	cursorPrev = "prev"
+)
+
+// PageCursors struct store encoded cursors to the next and previous page, empty cursor means there is no such page
+type PageCursors struct {
+	Next string
+	Prev string
+}
+
+// Decoded cursor, values are ordered as the pagination columns
+type cursor struct {
+	Direction string        `json:"d"`
+	Values    []interface{} `json:"v"`
+}
+
+// Keyset pagination state of the query
+type pagination struct {
+	cursor      *cursor
+	size        int
+	columns     []string
+	desc        bool
+	isFirstPage bool
+}
+
+// Paginate function is used to query page of size records after or before the cursor
+//	The records are ordered by orderCols (e.g. "createdon" or "createdon DESC") and the primary keys as tiebreaker,
+//	all order columns must have the same direction. Use empty cursor for the first page.
+//	Use DB.FindPage to get the cursors of the next and previous page.
+func Paginate(cursorStr string, size int, orderCols ...string) QueryOption {
+	return func(q *Query) (*Query, error) {
+		if size < 1 {
+			return nil, errors.New("Error: page size must be greater than zero")
+		}
+
+		p := &pagination{
+			size:        size,
+			isFirstPage: cursorStr == "",
+		}
+
+		for i, col := range orderCols {
+			fields := strings.Fields(col)
+			if len(fields) == 0 || len(fields) > 2 {
+				return nil, fmt.Errorf("Error: invalid order column %s", col)
+			}
+
+			desc := len(fields) == 2 && strings.ToUpper(fields[1]) == "DESC"
+			if len(fields) == 2 && !desc && strings.ToUpper(fields[1]) != "ASC" {
+				return nil, fmt.Errorf("Error: invalid order column %s", col)
+			}
+
+			if i > 0 && desc != p.desc {
+				return nil, errors.New("Error: all paginate order columns must have the same direction")
+			}
+
+			p.desc = desc
+			p.columns = append(p.columns, fields[0])
+		}
+
+		if q.modelPtr != nil {
+			for _, f := range q.modelPtr.PrimaryKeys {
+				if col := f.ColumnName(); !containsColumn(p.columns, col) {
+					p.columns = append(p.columns, col)
+				}
+			}
+		}
+
+		if len(p.columns) == 0 {
+			return nil, errors.New("Error: paginate requires order columns or model with primary key")
+		}
+
+		if !p.isFirstPage {
+			c, err := decodeCursor(cursorStr)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(c.Values) != len(p.columns) {
+				return nil, errors.New("Error: cursor does not match the order columns")
+			}
+			p.cursor = c
+		}
+
+		// Query backward in reversed order when moving to previous page, the result is reversed after scanning
+		backward := p.cursor != nil && p.cursor.Direction == cursorPrev
+		desc := p.desc != backward
+
+		if p.cursor != nil {
+			cols := []interface{}{}
+			for _, col := range p.columns {
+				cols = append(cols, Col(col))
+			}
+
+			var cond *Expression
+			if desc {
+				cond = IsLessThan(Tuple(cols...), Tuple(p.cursor.Values...))
+			} else {
+				cond = IsGreaterThan(Tuple(cols...), Tuple(p.cursor.Values...))
+			}
+			q.whereConditions = append(q.whereConditions, cond)
+		}
+
+		for _, col := range p.columns {
+			if desc {
+				q.orders = append(q.orders, fmt.Sprintf("%s DESC", col))
+			} else {
+				q.orders = append(q.orders, fmt.Sprintf("%s ASC", col))
+			}
+		}
+
+		q.limit = size
+		q.pagination = p
+
+		return q, nil
+	}
+}
+
+// FindPage method return records queried with Paginate query option and the cursors to the next and previous page
+//	Dest must be pointer to slice of pointer to struct.
+func (db *DB) FindPage(dest interface{}, opts ...QueryOption) (*PageCursors, error) {
+	destVal := reflect.ValueOf(dest)
+	if destVal.Kind() != reflect.Ptr || destVal.Elem().Kind() != reflect.Slice {
+		return nil, fmt.Errorf("Error: expected pointer to slice, found %v", destVal.Kind())
+	}
+	sliceVal := destVal.Elem()
+
+	// Reused slice is emptied, so its records are not used as conditions nor mixed with the page
+	sliceVal.Set(reflect.MakeSlice(sliceVal.Type(), 0, 0))
+
+	newDB, err := db.cloneWithOptions(dest, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	p := newDB.query.pagination
+	if p == nil {
+		return nil, errors.New("Error: FindPage requires Paginate query option")
+	}
+
+	if err := newDB.executeSelectQuery(); err != nil {
+		return nil, err
+	}
+
+	backward := p.cursor != nil && p.cursor.Direction == cursorPrev
+	if backward {
+		swap := reflect.Swapper(sliceVal.Interface())
+		for i, j := 0, sliceVal.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+
+	cursors := &PageCursors{}
+	if sliceVal.Len() == 0 {
+		return cursors, nil
+	}
+
+	isFull := sliceVal.Len() == p.size
+	if (!backward && isFull) || backward {
+		if cursors.Next, err = encodeCursor(cursorNext, sliceVal.Index(sliceVal.Len()-1).Interface(), p.columns); err != nil {
+			return nil, err
+		}
+	}
+
+	if (backward && isFull) || (!backward && !p.isFirstPage) {
+		if cursors.Prev, err = encodeCursor(cursorPrev, sliceVal.Index(0).Interface(), p.columns); err != nil {
+			return nil, err
+		}
+	}
+
+	return cursors, nil
+}
+
+// Page method return page n (starting from 1) of size records using limit and offset, and the total number of records
+//	Dest must be pointer to slice of pointer to struct.
+func (db *DB) Page(dest interface{}, n, size int, opts ...QueryOption) (int64, error) {
+	if n < 1 || size < 1 {
+		return 0, errors.New("Error: page number and size must be greater than zero")
+	}
+
+	destVal := reflect.ValueOf(dest)
+	if destVal.Kind() != reflect.Ptr || destVal.Elem().Kind() != reflect.Slice {
+		return 0, fmt.Errorf("Error: expected pointer to slice, found %v", destVal.Kind())
+	}
+
+	// Reused slice is emptied, so its records are not used as conditions nor mixed with the page
+	destVal.Elem().Set(reflect.MakeSlice(destVal.Elem().Type(), 0, size))
+
+	total, err := db.Count(dest, opts...)
+	if err != nil {
+		return 0, err
+	}
+
+	opts = append(opts, Limit(size), Offset((n-1)*size))
+	if err := db.Find(dest, opts...); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// Encode cursor from the order column values of the record
+func encodeCursor(direction string, record interface{}, columns []string) (string, error) {
+	_, m, err := model.NewModels(record)
+	if err != nil {
+		return "", err
+	}
+
+	values := []interface{}{}
+	for _, col := range columns {
+		name := col
+		if idx := strings.LastIndex(col, "."); idx >= 0 {
+			name = col[idx+1:]
+		}
+
+		f, ok := m.Fields[name]
+		if !ok {
+			return "", fmt.Errorf("Error: order column %s has no matching field in %s", col, m.Name)
+		}
+		values = append(values, f.Value.Interface())
+	}
+
+	b, err := json.Marshal(&cursor{Direction: direction, Values: values})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Decode cursor string, numbers are decoded as json.Number to keep their precision
+func decodeCursor(cursorStr string) (*cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursorStr)
+	if err != nil {
+		return nil, errors.New("Error: invalid cursor")
+	}
+
+	c := &cursor{}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(c); err != nil {
+		return nil, errors.New("Error: invalid cursor")
+	}
+
+	if c.Direction != cursorNext && c.Direction != cursorPrev {
+		return nil, errors.New("Error: invalid cursor")
+	}
+
+	for i, val := range c.Values {
+		if num, ok := val.(json.Number); ok {
+			c.Values[i] = num.String()
+		}
+	}
+
+	return c, nil
+}
+
+func containsColumn(columns []string, column string) bool {
+	for _, col := range columns {
+		if col == column || strings.HasSuffix(col, "."+column) {
+			return true
+		}
+	}
+
+	return false
+}
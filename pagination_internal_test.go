@@ -0,0 +1,154 @@
+package fury
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestPaginateQuery(t *testing.T) {
+	next, err := encodeCursor(cursorNext, &User{UserID: 10, Counter: 3}, []string{"counter", "userid"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prev, err := encodeCursor(cursorPrev, &User{UserID: 10, Counter: 3}, []string{"counter", "userid"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		cursor    string
+		orderCols []string
+		want      string
+		wantArgs  []interface{}
+	}{
+		{
+			"",
+			[]string{"counter"},
+			"SELECT * FROM user ORDER BY counter ASC, userid ASC LIMIT 2;",
+			nil,
+		},
+		{
+			next,
+			[]string{"counter"},
+			"SELECT * FROM user WHERE (counter, userid) > ($1, $2) ORDER BY counter ASC, userid ASC LIMIT 2;",
+			[]interface{}{"3", "10"},
+		},
+		{
+			prev,
+			[]string{"counter"},
+			"SELECT * FROM user WHERE (counter, userid) < ($1, $2) ORDER BY counter DESC, userid DESC LIMIT 2;",
+			[]interface{}{"3", "10"},
+		},
+		{
+			next,
+			[]string{"counter DESC", "userid DESC"},
+			"SELECT * FROM user WHERE (counter, userid) < ($1, $2) ORDER BY counter DESC, userid DESC LIMIT 2;",
+			[]interface{}{"3", "10"},
+		},
+	}
+
+	for _, tc := range cases {
+		q, err := NewQuery(&[]*User{})
+		if err != nil {
+			t.Error(err)
+		}
+
+		if _, err := Paginate(tc.cursor, 2, tc.orderCols...)(q); err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if err := q.prepareSelectQuery(); err != nil {
+			t.Error(err)
+		}
+
+		if tc.want != q.SQL || !reflect.DeepEqual(tc.wantArgs, q.args) {
+			t.Errorf("Error: expected %s and %v, found %s and %v", tc.want, tc.wantArgs, q.SQL, q.args)
+		}
+	}
+}
+
+func TestPaginateInvalidArguments(t *testing.T) {
+	cases := []struct {
+		cursor    string
+		size      int
+		orderCols []string
+	}{
+		{"", 0, []string{"counter"}},
+		{"", 10, []string{"counter ASC", "userid DESC"}},
+		{"", 10, []string{"counter SIDEWAYS"}},
+		{"not a cursor", 10, []string{"counter"}},
+	}
+
+	for _, tc := range cases {
+		q, err := NewQuery(&[]*User{})
+		if err != nil {
+			t.Error(err)
+		}
+
+		if _, err := Paginate(tc.cursor, tc.size, tc.orderCols...)(q); err == nil {
+			t.Error("Expected error found nil")
+		}
+	}
+}
+
+func TestCursorEncodeDecode(t *testing.T) {
+	str, err := encodeCursor(cursorPrev, &User{UserID: 10, Counter: 3}, []string{"user.counter", "userid"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := decodeCursor(str)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &cursor{Direction: cursorPrev, Values: []interface{}{"3", "10"}}
+	if !reflect.DeepEqual(want, c) {
+		t.Errorf("Error: expected %v, found %v", want, c)
+	}
+
+	if _, err := encodeCursor(cursorNext, &User{}, []string{"email"}); err == nil {
+		t.Error("Expected error found nil")
+	}
+}
+
+func TestPaginateReusedSlice(t *testing.T) {
+	db, d := newResultDB([]string{"userid", "counter"}, []driver.Value{int64(1), int64(1)}, []driver.Value{int64(2), int64(1)})
+
+	users := []*User{}
+	cursors, err := db.FindPage(&users, Paginate("", 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d.rows = [][]driver.Value{{int64(3), int64(1)}}
+	if cursors, err = db.FindPage(&users, Paginate(cursors.Next, 2)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(users) != 1 || users[0].UserID != 3 || cursors.Next != "" || cursors.Prev == "" {
+		t.Errorf("Error: unexpected page %v with cursors %v", users, cursors)
+	}
+
+	d.rows = [][]driver.Value{{int64(3), int64(1)}}
+	if _, err := db.Page(&users, 2, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(users) != 1 || users[0].UserID != 3 {
+		t.Errorf("Error: unexpected page %v", users)
+	}
+
+	want := []string{
+		"SELECT * FROM user ORDER BY userid ASC LIMIT 2;",
+		"SELECT * FROM user WHERE (userid) > ($1) ORDER BY userid ASC LIMIT 2;",
+		"SELECT COUNT(*) FROM user;",
+		"SELECT * FROM user LIMIT 2 OFFSET 2;",
+	}
+	if !reflect.DeepEqual(want, d.queries) {
+		t.Errorf("Error: expected %v, found %v", want, d.queries)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/nandaryanizar/fury/model"
 )
@@ -12,6 +14,15 @@ import (
 // QueryOption is return type for every main query and execute method
 type QueryOption func(q *Query) (*Query, error)
 
+// destinationType is the kind of value the query result is scanned to
+type destinationType int
+
+const (
+	modelDestination destinationType = iota
+	mapDestination
+	scalarDestination
+)
+
 // Query base struct
 type Query struct {
 	SQL             string
@@ -28,43 +39,411 @@ type Query struct {
 	useModelAsCond  bool
 	modelPtr        *model.Model
 	modelPtrCtr     int
+	destination     destinationType
+	strictColumns   bool
+	pagination      *pagination
+	joins           []join
+	tableAlias      string
+	havingConds     []interface{}
+	distinct        bool
+	distinctOn      []interface{}
+	fromQuery       *Query
+	ctes            []cte
+	compound        *compound
+	lock            *lock
+	updateColumns   []string
+	omitColumns     []string
+	updateValues    map[string]interface{}
+	allowFullTable  bool
+	rowsAffected    *int64
+	returning       []string
+	withDeleted     bool
+	hardDelete      bool
+	softDeletedAt   time.Time
+	clock           func() time.Time
 }
 
-// NewQuery return new Query literal
-func NewQuery(modelInterface interface{}) (*Query, error) {
-	m, mPtr, err := model.NewModels(modelInterface)
-	if err != nil {
-		return nil, err
+// lock struct to store row locking clause of SELECT query
+type lock struct {
+	strength string
+	tables   []string
+	wait     string
+}
+
+// Copy the lock, so lock option of cloned query does not modify the original
+func (l *lock) clone() *lock {
+	if l == nil {
+		return nil
 	}
 
+	return &lock{strength: l.strength, tables: append([]string(nil), l.tables...), wait: l.wait}
+}
+
+// compound struct to store queries combined with set operator such as UNION
+type compound struct {
+	operator string
+	queries  []*Query
+}
+
+// cte struct to store common table expression of the query
+type cte struct {
+	name      string
+	query     interface{}
+	recursive bool
+}
+
+// join struct to store JOIN clause of the query
+type join struct {
+	kind  string
+	table string
+	on    interface{}
+}
+
+// NewQuery return new Query literal with the query options applied
+//	Pointer to map[string]interface{} or pointer to slice of it is accepted as destination, but the table must be specified with Table
+//	The returned query can be used as subquery operand of expression, in Exists, or as derived table in Table
+func NewQuery(modelInterface interface{}, opts ...QueryOption) (*Query, error) {
 	q := &Query{
-		models:         m,
-		useModelAsCond: true,
-		scanTo:         modelInterface,
-		modelPtr:       mPtr,
-		modelPtrCtr:    -1,
+		scanTo:      modelInterface,
+		modelPtrCtr: -1,
+		destination: mapDestination,
+	}
+
+	if !isMapDestination(modelInterface) {
+		m, mPtr, err := model.NewModels(modelInterface)
+		if err != nil {
+			return nil, err
+		}
+
+		q = &Query{
+			models:         m,
+			useModelAsCond: true,
+			scanTo:         modelInterface,
+			modelPtr:       mPtr,
+			modelPtrCtr:    -1,
+		}
+	}
+
+	for _, opt := range opts {
+		if _, err := opt(q); err != nil {
+			return nil, err
+		}
 	}
 
 	return q, nil
 }
 
+// ToString method convert the query to parenthesized SELECT subquery and slice of arguments
+//	The arguments are merged to the outer query, so the placeholders are numbered with the outer query arguments
+func (q *Query) ToString() (string, []interface{}, error) {
+	query := q.clone()
+	selectQuery, err := query.buildSelectQuery()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return fmt.Sprintf("(%s)", selectQuery), query.args, nil
+}
+
+// Create new query which scan single column to pointer to slice of scalar value
+func newScalarQuery(dest interface{}, column string) (*Query, error) {
+	reflectVal := reflect.ValueOf(dest)
+	if reflectVal.Kind() != reflect.Ptr || reflectVal.Elem().Kind() != reflect.Slice {
+		return nil, fmt.Errorf("Error: expected pointer to slice, found %v", reflectVal.Kind())
+	}
+
+	if column == "" {
+		return nil, errors.New("Error: missing column name")
+	}
+
+	return &Query{
+		scanTo:      dest,
+		columns:     []interface{}{column},
+		modelPtrCtr: -1,
+		destination: scalarDestination,
+	}, nil
+}
+
+// Check if destination is pointer to map[string]interface{} or pointer to slice of map[string]interface{}
+func isMapDestination(dest interface{}) bool {
+	reflectType := reflect.TypeOf(dest)
+	if reflectType == nil || reflectType.Kind() != reflect.Ptr {
+		return false
+	}
+
+	reflectType = reflectType.Elem()
+	if reflectType.Kind() == reflect.Slice {
+		reflectType = reflectType.Elem()
+	}
+
+	return reflectType.Kind() == reflect.Map && reflectType.Key().Kind() == reflect.String && reflectType.Elem().Kind() == reflect.Interface
+}
+
 // Table specify which table to query
 //	using this function means that query will not use model to specify query condition
-func Table(tableName string) QueryOption {
+//	Query created by NewQuery is also accepted as derived table of SELECT query, use Alias to name it
+func Table(table interface{}) QueryOption {
 	return func(q *Query) (*Query, error) {
-		q.tableName = tableName
+		switch t := table.(type) {
+		case string:
+			q.tableName = t
+		case *Query:
+			q.fromQuery = t
+		default:
+			return nil, errors.New("Unsupported table type")
+		}
+
 		q.useModelAsCond = false
 		return q, nil
 	}
 }
 
+// With function is used to add common table expression, the name may contain column list (e.g. "tree(id, parent)")
+// 	Supported subquery type: Query created by NewQuery, string
+func With(name string, subquery interface{}) QueryOption {
+	return newCTE(name, subquery, false)
+}
+
+// WithRecursive function is used to add recursive common table expression, the query become WITH RECURSIVE query
+// 	Supported subquery type: Query created by NewQuery, string
+func WithRecursive(name string, subquery interface{}) QueryOption {
+	return newCTE(name, subquery, true)
+}
+
+func newCTE(name string, subquery interface{}, recursive bool) QueryOption {
+	return func(q *Query) (*Query, error) {
+		if name == "" {
+			return nil, errors.New("Error: common table expression name cannot be empty")
+		}
+
+		switch subquery.(type) {
+		case sqlStringer, string:
+			q.ctes = append(q.ctes, cte{name: name, query: subquery, recursive: recursive})
+		default:
+			return nil, errors.New("Unsupported common table expression type")
+		}
+
+		return q, nil
+	}
+}
+
+// Union function combine the results of the queries created by NewQuery, removing duplicate rows
+// 	The combined query replace the main SELECT query, so OrderBy, Limit and Offset apply to the combined result
+//	while the conditions must be added to each query. Use NewQuery with this option to nest different set operators.
+func Union(queries ...*Query) QueryOption {
+	return newCompound("UNION", queries...)
+}
+
+// UnionAll function combine the results of the queries created by NewQuery, keeping duplicate rows
+func UnionAll(queries ...*Query) QueryOption {
+	return newCompound("UNION ALL", queries...)
+}
+
+// Intersect function return rows that are in the results of all queries created by NewQuery
+func Intersect(queries ...*Query) QueryOption {
+	return newCompound("INTERSECT", queries...)
+}
+
+// Except function return rows of the first query result that are not in the results of the other queries
+func Except(queries ...*Query) QueryOption {
+	return newCompound("EXCEPT", queries...)
+}
+
+func newCompound(operator string, queries ...*Query) QueryOption {
+	return func(q *Query) (*Query, error) {
+		if len(queries) < 2 {
+			return nil, fmt.Errorf("Error: %s requires at least two queries", operator)
+		}
+
+		for _, query := range queries {
+			if query == nil {
+				return nil, fmt.Errorf("Error: %s query cannot be nil", operator)
+			}
+		}
+
+		q.compound = &compound{operator: operator, queries: queries}
+		return q, nil
+	}
+}
+
+// ForUpdate function is used to lock the selected rows for update, optionally only rows of the specified tables
+// 	Locking query option can only be used inside transaction
+func ForUpdate(tables ...string) QueryOption {
+	return newLock("UPDATE", tables...)
+}
+
+// ForShare function is used to lock the selected rows in share mode, optionally only rows of the specified tables
+// 	Locking query option can only be used inside transaction
+func ForShare(tables ...string) QueryOption {
+	return newLock("SHARE", tables...)
+}
+
+// SkipLocked function is used to skip rows that cannot be locked immediately, use it with ForUpdate or ForShare
+func SkipLocked() QueryOption {
+	return newLockWait("SKIP LOCKED")
+}
+
+// NoWait function is used to return error instead of waiting when rows cannot be locked immediately, use it with ForUpdate or ForShare
+func NoWait() QueryOption {
+	return newLockWait("NOWAIT")
+}
+
+func newLock(strength string, tables ...string) QueryOption {
+	return func(q *Query) (*Query, error) {
+		if q.lock == nil {
+			q.lock = &lock{}
+		}
+
+		q.lock.strength = strength
+		q.lock.tables = append(q.lock.tables, tables...)
+		return q, nil
+	}
+}
+
+func newLockWait(wait string) QueryOption {
+	return func(q *Query) (*Query, error) {
+		if q.lock == nil {
+			q.lock = &lock{}
+		}
+
+		q.lock.wait = wait
+		return q, nil
+	}
+}
+
+// Set type is columns and values to be updated by UpdateWhere, value may be SQL expression such as Add(Col("counter"), 1)
+type Set map[string]interface{}
+
+// AllowFullTable function allow UPDATE and DELETE query without filter to modify all records of the table
+func AllowFullTable() QueryOption {
+	return func(q *Query) (*Query, error) {
+		q.allowFullTable = true
+		return q, nil
+	}
+}
+
+// Unscoped function disable soft delete, SELECT query include soft deleted records and DELETE query remove the records
+func Unscoped() QueryOption {
+	return func(q *Query) (*Query, error) {
+		q.withDeleted = true
+		q.hardDelete = true
+		return q, nil
+	}
+}
+
+// WithDeleted function make SELECT query include soft deleted records
+func WithDeleted() QueryOption {
+	return func(q *Query) (*Query, error) {
+		q.withDeleted = true
+		return q, nil
+	}
+}
+
+// HardDelete function make DELETE query remove the records of model with soft delete field
+func HardDelete() QueryOption {
+	return func(q *Query) (*Query, error) {
+		q.hardDelete = true
+		return q, nil
+	}
+}
+
+// RowsAffected function store the total number of rows affected by INSERT, UPDATE or DELETE query to count
+func RowsAffected(count *int64) QueryOption {
+	return func(q *Query) (*Query, error) {
+		if count == nil {
+			return nil, errors.New("Error: rows affected destination cannot be nil")
+		}
+
+		q.rowsAffected = count
+		return q, nil
+	}
+}
+
+// Returning function add RETURNING clause to UPDATE and DELETE query, the returned row is scanned back to the model
+func Returning(columns ...string) QueryOption {
+	return func(q *Query) (*Query, error) {
+		if len(columns) < 1 {
+			return nil, errors.New("Error: returning requires at least one column")
+		}
+
+		q.returning = append(q.returning, columns...)
+		return q, nil
+	}
+}
+
+// Columns function restrict UPDATE query to the specified columns, including columns with zero value
+func Columns(columns ...string) QueryOption {
+	return func(q *Query) (*Query, error) {
+		q.updateColumns = append(q.updateColumns, columns...)
+		return q, nil
+	}
+}
+
+// Omit function exclude the specified columns from UPDATE query
+func Omit(columns ...string) QueryOption {
+	return func(q *Query) (*Query, error) {
+		q.omitColumns = append(q.omitColumns, columns...)
+		return q, nil
+	}
+}
+
+// Alias function set alias of the queried table, e.g. FROM account AS a
+// 	Unlike Table, the model is still used to specify query condition using the alias
+func Alias(alias string) QueryOption {
+	return func(q *Query) (*Query, error) {
+		if alias == "" {
+			return nil, errors.New("Error: alias cannot be empty")
+		}
+		q.tableAlias = alias
+		return q, nil
+	}
+}
+
+// Join function is used to add INNER JOIN clause, table may contain alias (e.g. "orders AS o")
+// 	Supported on condition type: Expression, LogicalExpression, ExistsExpression, string. Use Col to compare columns of both tables
+func Join(table string, on interface{}) QueryOption {
+	return newJoin("INNER JOIN", table, on)
+}
+
+// LeftJoin function is used to add LEFT JOIN clause
+func LeftJoin(table string, on interface{}) QueryOption {
+	return newJoin("LEFT JOIN", table, on)
+}
+
+// RightJoin function is used to add RIGHT JOIN clause
+func RightJoin(table string, on interface{}) QueryOption {
+	return newJoin("RIGHT JOIN", table, on)
+}
+
+// FullJoin function is used to add FULL JOIN clause
+func FullJoin(table string, on interface{}) QueryOption {
+	return newJoin("FULL JOIN", table, on)
+}
+
+func newJoin(kind, table string, on interface{}) QueryOption {
+	return func(q *Query) (*Query, error) {
+		if table == "" {
+			return nil, errors.New("Error: join table cannot be empty")
+		}
+
+		switch on.(type) {
+		case *Expression, *LogicalExpression, *ExistsExpression, string:
+			q.joins = append(q.joins, join{kind: kind, table: table, on: on})
+		default:
+			return nil, errors.New("Unsupported join conditions type")
+		}
+
+		return q, nil
+	}
+}
+
 // Where function is used to add new query condition
-// 	Supported expression condition type: Expression, LogicalExpression, string
+// 	Supported expression condition type: Expression, LogicalExpression, ExistsExpression, string
 //  Use Expression and LogicalExpression for type safety
 func Where(conditions interface{}) QueryOption {
 	return func(q *Query) (*Query, error) {
 		switch conditions.(type) {
-		case *Expression, *LogicalExpression, string:
+		case *Expression, *LogicalExpression, *ExistsExpression, string:
 			q.whereConditions = append(q.whereConditions, conditions)
 		default:
 			return nil, errors.New("Unsupported expression conditions type")
@@ -74,6 +453,41 @@ func Where(conditions interface{}) QueryOption {
 	}
 }
 
+// Having function is used to add condition on grouped rows
+// 	Supported expression condition type: Expression, LogicalExpression, ExistsExpression, string
+func Having(conditions interface{}) QueryOption {
+	return func(q *Query) (*Query, error) {
+		switch conditions.(type) {
+		case *Expression, *LogicalExpression, *ExistsExpression, string:
+			q.havingConds = append(q.havingConds, conditions)
+		default:
+			return nil, errors.New("Unsupported expression conditions type")
+		}
+
+		return q, nil
+	}
+}
+
+// Distinct function is used to remove duplicate rows from the result
+func Distinct() QueryOption {
+	return func(q *Query) (*Query, error) {
+		q.distinct = true
+		return q, nil
+	}
+}
+
+// DistinctOn function is used to keep only the first row of each set of rows with the same columns value
+// 	This is PostgreSQL DISTINCT ON (columns) query, use OrderBy to specify which row is the first
+func DistinctOn(columns ...interface{}) QueryOption {
+	return func(q *Query) (*Query, error) {
+		if len(columns) == 0 {
+			return nil, errors.New("Error: distinct on requires at least one column")
+		}
+		q.distinctOn = append(q.distinctOn, columns...)
+		return q, nil
+	}
+}
+
 // Select function is used to specify columns in query
 func Select(columns ...interface{}) QueryOption {
 	return func(q *Query) (*Query, error) {
@@ -82,6 +496,15 @@ func Select(columns ...interface{}) QueryOption {
 	}
 }
 
+// StrictColumns function make the query return error when result column has no matching field in the model
+//	By default, such column is discarded
+func StrictColumns() QueryOption {
+	return func(q *Query) (*Query, error) {
+		q.strictColumns = true
+		return q, nil
+	}
+}
+
 // Limit function is used to add limit query
 func Limit(limit int) QueryOption {
 	return func(q *Query) (*Query, error) {
@@ -121,22 +544,44 @@ func OrderBy(columns ...interface{}) QueryOption {
 }
 
 // Create new context
+//	Slices are copied, as building the query append to them while the original query may be used concurrently, e.g. as subquery
 func (q *Query) clone() *Query {
 	return &Query{
 		SQL:             "",
 		tableName:       q.tableName,
 		models:          q.models,
-		columns:         q.columns,
+		columns:         append([]interface{}(nil), q.columns...),
 		scanTo:          q.scanTo,
-		whereConditions: q.whereConditions,
+		whereConditions: append([]interface{}(nil), q.whereConditions...),
 		args:            []interface{}{},
 		limit:           q.limit,
 		offset:          q.offset,
-		groups:          q.groups,
-		orders:          q.orders,
+		groups:          append([]interface{}(nil), q.groups...),
+		orders:          append([]interface{}(nil), q.orders...),
 		useModelAsCond:  q.useModelAsCond,
 		modelPtr:        q.modelPtr,
 		modelPtrCtr:     q.modelPtrCtr,
+		destination:     q.destination,
+		strictColumns:   q.strictColumns,
+		pagination:      q.pagination,
+		joins:           append([]join(nil), q.joins...),
+		tableAlias:      q.tableAlias,
+		havingConds:     append([]interface{}(nil), q.havingConds...),
+		distinct:        q.distinct,
+		distinctOn:      append([]interface{}(nil), q.distinctOn...),
+		fromQuery:       q.fromQuery,
+		ctes:            append([]cte(nil), q.ctes...),
+		compound:        q.compound,
+		lock:            q.lock.clone(),
+		updateColumns:   append([]string(nil), q.updateColumns...),
+		omitColumns:     append([]string(nil), q.omitColumns...),
+		updateValues:    q.updateValues,
+		allowFullTable:  q.allowFullTable,
+		rowsAffected:    q.rowsAffected,
+		returning:       append([]string(nil), q.returning...),
+		withDeleted:     q.withDeleted,
+		hardDelete:      q.hardDelete,
+		clock:           q.clock,
 	}
 }
 
@@ -214,8 +659,8 @@ func (q *Query) addPKWhereConditions() error {
 			val = f.Value.Elem()
 		}
 
-		key := fmt.Sprintf("%s.%s", q.modelPtr.Name, strings.ToLower(f.Properties.Name))
-		whereConds = append(whereConds, IsEqualsTo(key, val.Interface()))
+		key := fmt.Sprintf("%s.%s", q.modelPtr.Name, f.ColumnName())
+		whereConds = append(whereConds, IsEqualsTo(key, model.BindValue(val)))
 	}
 
 	if len(whereConds) > 0 {
@@ -225,6 +670,62 @@ func (q *Query) addPKWhereConditions() error {
 	return nil
 }
 
+// Get the qualified soft delete column of the model, empty if the model has no soft delete field
+//	Table name with alias (e.g. "account AS a") is qualified by the alias
+func (q *Query) getSoftDeleteColumn(tableName string) string {
+	if q.modelPtr == nil || q.modelPtr.SoftDelete == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s.%s", tableReference(tableName), q.modelPtr.SoftDelete.ColumnName())
+}
+
+// Get the name used to refer to the table, which is the alias of table name with alias (e.g. "account AS a" or "account a")
+func tableReference(tableName string) string {
+	fields := strings.Fields(tableName)
+	switch {
+	case len(fields) == 3 && strings.EqualFold(fields[1], "AS"):
+		return fields[2]
+	case len(fields) == 2:
+		return fields[1]
+	}
+
+	return tableName
+}
+
+// Exclude soft deleted records from SELECT query unless WithDeleted or Unscoped option is used
+func (q *Query) addSoftDeleteCondition() error {
+	if q.withDeleted || q.fromQuery != nil || q.modelPtr == nil || q.modelPtr.SoftDelete == nil {
+		return nil
+	}
+
+	tableName := q.tableAlias
+	if tableName == "" {
+		name, err := q.getTableName()
+		if err != nil {
+			return err
+		}
+		tableName = name
+	}
+
+	q.whereConditions = append(q.whereConditions, fmt.Sprintf("%s IS NULL", q.getSoftDeleteColumn(tableName)))
+	return nil
+}
+
+// Get current time from the clock of DB configuration
+func (q *Query) now() time.Time {
+	if q.clock != nil {
+		return q.clock()
+	}
+
+	return time.Now()
+}
+
+// Delete query of model with soft delete field update the field instead, unless HardDelete or Unscoped option is used
+func (q *Query) isSoftDelete() bool {
+	return !q.hardDelete && q.modelPtr != nil && q.modelPtr.SoftDelete != nil
+}
+
 func (q *Query) addAllPKWhereConditions() error {
 	if q.models == nil || len(q.models) < 1 {
 		return nil
@@ -248,8 +749,13 @@ func (q *Query) addAllPKWhereConditions() error {
 				val = f.Value.Elem()
 			}
 
-			key := fmt.Sprintf("%s.%s", m.Name, strings.ToLower(f.Properties.Name))
-			whereConds = append(whereConds, IsEqualsTo(key, val.Interface()))
+			tableRef := m.Name
+			if q.tableAlias != "" {
+				tableRef = q.tableAlias
+			}
+
+			key := fmt.Sprintf("%s.%s", tableRef, f.ColumnName())
+			whereConds = append(whereConds, IsEqualsTo(key, model.BindValue(val)))
 		}
 
 		if len(whereConds) > 0 {
@@ -279,10 +785,65 @@ func (q *Query) prepareSelectColumn() (string, error) {
 		out = "*"
 	}
 
+	if len(q.distinctOn) > 0 {
+		return fmt.Sprintf("SELECT DISTINCT ON (%s) %s", joinColumns(q.distinctOn), out), nil
+	}
+
+	if q.distinct {
+		return fmt.Sprintf("SELECT DISTINCT %s", out), nil
+	}
+
 	return fmt.Sprintf("SELECT %s", out), nil
 }
 
+// Join string columns with comma separator
+func joinColumns(columns []interface{}) string {
+	out := ""
+	for i, col := range columns {
+		if colStr, ok := col.(string); ok {
+			if i > 0 {
+				out += ", "
+			}
+			out += colStr
+		}
+	}
+
+	return out
+}
+
+// Prepare table reference of SELECT query, either table name or derived table with its alias
+func (q *Query) prepareFromQuery() (string, error) {
+	if q.fromQuery != nil {
+		if q.tableAlias == "" {
+			return "", errors.New("Error: derived table requires alias")
+		}
+
+		subquery, args, err := q.fromQuery.ToString()
+		if err != nil {
+			return "", err
+		}
+
+		q.args = append(q.args, args...)
+		return fmt.Sprintf("%s AS %s", subquery, q.tableAlias), nil
+	}
+
+	tableName, err := q.getTableName()
+	if err != nil {
+		return "", err
+	}
+
+	if q.tableAlias != "" {
+		tableName = fmt.Sprintf("%s AS %s", tableName, q.tableAlias)
+	}
+
+	return tableName, nil
+}
+
 func (q *Query) getTableName() (string, error) {
+	if q.fromQuery != nil {
+		return "", errors.New("Error: derived table is only supported in select query")
+	}
+
 	if q.tableName != "" {
 		return q.tableName, nil
 	}
@@ -294,6 +855,23 @@ func (q *Query) getTableName() (string, error) {
 	return "", errors.New("Error: unspecified table name")
 }
 
+// Convert single condition to string and slice of arguments
+// 	Supported condition type: Expression, LogicalExpression, ExistsExpression, string
+func conditionToString(cond interface{}) (string, []interface{}, error) {
+	switch exp := cond.(type) {
+	case *Expression:
+		return exp.ToString()
+	case *LogicalExpression:
+		return exp.ToString()
+	case *ExistsExpression:
+		return exp.ToString()
+	case string:
+		return exp, []interface{}{}, nil
+	}
+
+	return "", nil, errors.New("Unsupported expression conditions type")
+}
+
 func (q *Query) prepareWhereQuery() (string, error) {
 	out := ""
 	for i, cond := range q.whereConditions {
@@ -301,28 +879,138 @@ func (q *Query) prepareWhereQuery() (string, error) {
 			out += " AND "
 		}
 
-		if exp, ok := cond.(*Expression); ok {
-			expStr, args, err := exp.ToString()
-			if err != nil {
-				return "", err
-			}
+		expStr, args, err := conditionToString(cond)
+		if err != nil {
+			return "", err
+		}
 
-			q.args = append(q.args, args...)
-			out += expStr
-		} else if exp, ok := cond.(*LogicalExpression); ok {
-			expStr, args, err := exp.ToString()
-			if err != nil {
-				return "", err
-			}
+		q.args = append(q.args, args...)
+		out += expStr
+	}
 
-			q.args = append(q.args, args...)
-			out += expStr
-		} else if exp, ok := cond.(string); ok {
-			out += exp
+	return fmt.Sprintf(" WHERE %s", out), nil
+}
+
+// Prepare WHERE clause of UPDATE and DELETE query, query without filter is only allowed with AllowFullTable option
+func (q *Query) prepareFilterQuery(statement string) (string, error) {
+	if len(q.whereConditions) < 1 {
+		if q.allowFullTable {
+			return "", nil
 		}
+
+		return "", fmt.Errorf("Unsupported %s without filter", statement)
 	}
 
-	return fmt.Sprintf(" WHERE %s", out), nil
+	return q.prepareWhereQuery()
+}
+
+// Prepare RETURNING clause of UPDATE and DELETE query
+func (q *Query) prepareReturningQuery() string {
+	if len(q.returning) < 1 {
+		return ""
+	}
+
+	return " RETURNING " + strings.Join(q.returning, ", ")
+}
+
+// Prepare WITH clause, the arguments of common table expressions come before the main statement arguments
+func (q *Query) prepareWithQuery() (string, error) {
+	out := ""
+	recursive := false
+	for i, c := range q.ctes {
+		if i > 0 {
+			out += ", "
+		}
+
+		var subquery string
+		var args []interface{}
+		var err error
+		if str, ok := c.query.(string); ok {
+			subquery, args = fmt.Sprintf("(%s)", str), []interface{}{}
+		} else if exp, ok := c.query.(sqlStringer); ok {
+			subquery, args, err = exp.ToString()
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		q.args = append(q.args, args...)
+		out += fmt.Sprintf("%s AS %s", c.name, subquery)
+		recursive = recursive || c.recursive
+	}
+
+	if len(out) == 0 {
+		return "", nil
+	}
+
+	if recursive {
+		return fmt.Sprintf("WITH RECURSIVE %s ", out), nil
+	}
+
+	return fmt.Sprintf("WITH %s ", out), nil
+}
+
+// Prepare queries combined with set operator, the conditions belong to each query so the main query must not have any
+func (q *Query) prepareCompoundQuery() (string, error) {
+	if len(q.whereConditions) > 0 || len(q.joins) > 0 || len(q.groups) > 0 || len(q.havingConds) > 0 || q.fromQuery != nil {
+		return "", fmt.Errorf("Error: conditions of %s query must be added to each combined query", q.compound.operator)
+	}
+
+	out := ""
+	for i, query := range q.compound.queries {
+		if i > 0 {
+			out += fmt.Sprintf(" %s ", q.compound.operator)
+		}
+
+		queryStr, args, err := query.ToString()
+		if err != nil {
+			return "", err
+		}
+
+		q.args = append(q.args, args...)
+		out += queryStr
+	}
+
+	return out, nil
+}
+
+func (q *Query) prepareHavingQuery() (string, error) {
+	out := ""
+	for i, cond := range q.havingConds {
+		if i > 0 {
+			out += " AND "
+		}
+
+		expStr, args, err := conditionToString(cond)
+		if err != nil {
+			return "", err
+		}
+
+		q.args = append(q.args, args...)
+		out += expStr
+	}
+
+	if len(out) > 0 {
+		out = fmt.Sprintf(" HAVING %s", out)
+	}
+
+	return out, nil
+}
+
+func (q *Query) prepareJoinQuery() (string, error) {
+	out := ""
+	for _, j := range q.joins {
+		onStr, args, err := conditionToString(j.on)
+		if err != nil {
+			return "", err
+		}
+
+		q.args = append(q.args, args...)
+		out += fmt.Sprintf(" %s %s ON %s", j.kind, j.table, onStr)
+	}
+
+	return out, nil
 }
 
 func (q *Query) prepareLimitOffsetQuery() string {
@@ -339,6 +1027,27 @@ func (q *Query) prepareLimitOffsetQuery() string {
 	return out
 }
 
+func (q *Query) prepareLockQuery() (string, error) {
+	if q.lock == nil {
+		return "", nil
+	}
+
+	if q.lock.strength == "" {
+		return "", fmt.Errorf("Error: %s requires ForUpdate or ForShare", q.lock.wait)
+	}
+
+	out := fmt.Sprintf(" FOR %s", q.lock.strength)
+	if len(q.lock.tables) > 0 {
+		out += fmt.Sprintf(" OF %s", strings.Join(q.lock.tables, ", "))
+	}
+
+	if q.lock.wait != "" {
+		out += fmt.Sprintf(" %s", q.lock.wait)
+	}
+
+	return out, nil
+}
+
 func (q *Query) prepareGroupByQuery() string {
 	out := ""
 	for i, col := range q.groups {
@@ -381,33 +1090,158 @@ func (q *Query) replaceSQLPlaceholder() {
 	}
 }
 
-func (q *Query) prepareSelectQuery() error {
+// Build SELECT query without terminator and placeholder replacement, so it can be wrapped by other query
+func (q *Query) buildSelectQuery() (string, error) {
+	withQuery, err := q.prepareWithQuery()
+	if err != nil {
+		return "", err
+	}
+
+	if q.compound != nil {
+		if q.lock != nil {
+			return "", fmt.Errorf("Error: unsupported locking query option with %s query", q.compound.operator)
+		}
+
+		compoundQuery, err := q.prepareCompoundQuery()
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("%s%s%s%s", withQuery, compoundQuery, q.prepareOrderByQuery(), q.prepareLimitOffsetQuery()), nil
+	}
+
 	selectColumn, err := q.prepareSelectColumn()
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	tableName, err := q.getTableName()
+	tableName, err := q.prepareFromQuery()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	if q.useModelAsCond {
 		if err := q.addAllPKWhereConditions(); err != nil {
-			return err
+			return "", err
 		}
 	}
 
-	whereQuery, err := q.prepareWhereQuery()
+	if err := q.addSoftDeleteCondition(); err != nil {
+		return "", err
+	}
+
+	joinQuery, err := q.prepareJoinQuery()
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	whereQuery := ""
+	if len(q.whereConditions) > 0 {
+		whereQuery, err = q.prepareWhereQuery()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// HAVING arguments must be appended after WHERE arguments
+	havingQuery, err := q.prepareHavingQuery()
+	if err != nil {
+		return "", err
+	}
+
+	lockQuery, err := q.prepareLockQuery()
+	if err != nil {
+		return "", err
 	}
 
 	limitOffsetQuery := q.prepareLimitOffsetQuery()
 	groupByQuery := q.prepareGroupByQuery()
 	orderByQuery := q.prepareOrderByQuery()
 
-	q.SQL = fmt.Sprintf("%s FROM %s%s%s%s%s;", selectColumn, tableName, whereQuery, groupByQuery, orderByQuery, limitOffsetQuery)
+	return fmt.Sprintf("%s%s FROM %s%s%s%s%s%s%s%s", withQuery, selectColumn, tableName, joinQuery, whereQuery, groupByQuery, havingQuery, orderByQuery, limitOffsetQuery, lockQuery), nil
+}
+
+func (q *Query) prepareSelectQuery() error {
+	selectQuery, err := q.buildSelectQuery()
+	if err != nil {
+		return err
+	}
+
+	q.SQL = fmt.Sprintf("%s;", selectQuery)
+	q.replaceSQLPlaceholder()
+
+	return nil
+}
+
+// Prepare aggregate query such as SUM(column), ordering and limit are discarded as the query return single row
+//	Grouped query is rejected as it return one row per group
+func (q *Query) prepareAggregateQuery(function, column string) error {
+	if column == "" {
+		return fmt.Errorf("Error: missing column for %s aggregate", function)
+	}
+
+	if len(q.groups) > 0 {
+		return fmt.Errorf("Error: unsupported %s aggregate with GroupBy, use Find with the aggregate column instead", function)
+	}
+
+	if q.lock != nil {
+		return fmt.Errorf("Error: unsupported locking query option with %s aggregate", function)
+	}
+
+	q.orders = nil
+	q.limit = 0
+	q.offset = 0
+
+	if q.compound != nil {
+		compoundQuery, err := q.buildSelectQuery()
+		if err != nil {
+			return err
+		}
+
+		q.SQL = fmt.Sprintf("SELECT %s(%s) FROM (%s) AS aggregate_query;", function, column, compoundQuery)
+		q.replaceSQLPlaceholder()
+
+		return nil
+	}
+
+	q.columns = []interface{}{fmt.Sprintf("%s(%s)", function, column)}
+
+	return q.prepareSelectQuery()
+}
+
+// Prepare COUNT(*) query, grouped or limited query is counted as subquery so the count match the number of rows returned
+func (q *Query) prepareCountQuery() error {
+	if q.lock != nil {
+		return errors.New("Error: unsupported locking query option with COUNT query")
+	}
+
+	if len(q.groups) == 0 && q.limit == 0 && q.offset == 0 && !q.distinct && len(q.distinctOn) == 0 && q.compound == nil {
+		return q.prepareAggregateQuery("COUNT", "*")
+	}
+
+	selectQuery, err := q.buildSelectQuery()
+	if err != nil {
+		return err
+	}
+
+	q.SQL = fmt.Sprintf("SELECT COUNT(*) FROM (%s) AS count_query;", selectQuery)
+	q.replaceSQLPlaceholder()
+
+	return nil
+}
+
+// Prepare SELECT EXISTS(...) query, the subquery only need to find the first record
+func (q *Query) prepareExistsQuery() error {
+	q.columns = []interface{}{"1"}
+	q.orders = nil
+	q.limit = 1
+
+	selectQuery, err := q.buildSelectQuery()
+	if err != nil {
+		return err
+	}
+
+	q.SQL = fmt.Sprintf("SELECT EXISTS(%s);", selectQuery)
 	q.replaceSQLPlaceholder()
 
 	return nil
@@ -419,6 +1253,16 @@ func (q *Query) getColumnsNamesAndValues(includeAutoInc bool) ([]string, []inter
 
 func (q *Query) prepareInsertQuery() error {
 	query := q.clone()
+	if query.modelPtr != nil {
+		if err := query.modelPtr.SetCreatedTimestamps(query.now()); err != nil {
+			return err
+		}
+
+		if err := query.modelPtr.Validate(); err != nil {
+			return err
+		}
+	}
+
 	cols, args := query.getColumnsNamesAndValues(false)
 	if len(cols) != len(args) {
 		return errors.New("Columns and argument length not match")
@@ -444,30 +1288,172 @@ func (q *Query) prepareInsertQuery() error {
 		valueQuery += fmt.Sprintf("$%d", i+1)
 	}
 
-	q.SQL = fmt.Sprintf("INSERT INTO %s(%s) VALUES(%s);", tableName, columnQuery, valueQuery)
+	q.SQL = fmt.Sprintf("INSERT INTO %s(%s) VALUES(%s)%s;", tableName, columnQuery, valueQuery, query.prepareReturningQuery())
 	q.args = query.args
 
 	return nil
 }
 
+// Update query of model with version field use optimistic locking, UpdateMap and UpdateWhere only increment the version without checking it
+func (q *Query) isVersionedUpdate() bool {
+	return q.updateValues == nil && q.modelPtr != nil && q.modelPtr.Version != nil
+}
+
+// Check if every key of update map has matching field in the model, update map without model is not checked
+func (q *Query) checkUpdateValueColumns() error {
+	if q.modelPtr == nil {
+		return nil
+	}
+
+	for col := range q.updateValues {
+		if _, ok := q.modelPtr.Fields[col]; !ok {
+			return fmt.Errorf("Error: column %s has no matching field in %s", col, q.modelPtr.Name)
+		}
+	}
+
+	return nil
+}
+
+// Get update map values which can be validated, SQL value such as Col or Add is not validated as it is computed by database
+func validatedUpdateValues(cols []string, values []interface{}) map[string]interface{} {
+	validated := map[string]interface{}{}
+	for i, col := range cols {
+		if _, ok := values[i].(sqlStringer); !ok {
+			validated[col] = values[i]
+		}
+	}
+
+	return validated
+}
+
+// Get columns and values of UPDATE query, either from the update map or from the model fields
+//	Value from update map may be SQL expression such as Add(Col("counter"), 1)
+func (q *Query) getUpdateColumnsAndValues() ([]string, []interface{}, error) {
+	if q.updateValues != nil {
+		// Keys are written as column names, so they must match the model fields to prevent SQL injection
+		if err := q.checkUpdateValueColumns(); err != nil {
+			return nil, nil, err
+		}
+
+		cols := []string{}
+		for col := range q.updateValues {
+			if !containsString(q.omitColumns, col) {
+				cols = append(cols, col)
+			}
+		}
+		sort.Strings(cols)
+
+		values := []interface{}{}
+		for _, col := range cols {
+			values = append(values, q.updateValues[col])
+		}
+
+		return cols, values, nil
+	}
+
+	if q.modelPtr == nil {
+		return nil, nil, errors.New("Error: update requires model")
+	}
+
+	// Model values are always bound as arguments
+	cols, args, err := q.modelPtr.GetUpdateColumnNamesAndValues(q.updateColumns, q.omitColumns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values := []interface{}{}
+	for _, arg := range args {
+		values = append(values, boundValue{arg})
+	}
+
+	return cols, values, nil
+}
+
+// boundValue wrap value so it is always bound as argument even if it can render itself as SQL
+type boundValue struct {
+	value interface{}
+}
+
+// ToString method return placeholder and the wrapped value as argument
+func (bv boundValue) ToString() (string, []interface{}, error) {
+	return "?", []interface{}{bv.value}, nil
+}
+
+func containsString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (q *Query) prepareUpdateQuery() error {
 	query := q.clone()
-	cols, args := query.getColumnsNamesAndValues(true)
-	if len(cols) != len(args) {
-		return errors.New("Columns and argument length not match")
+	withQuery, err := query.prepareWithQuery()
+	if err != nil {
+		return err
 	}
-	query.args = append(args, query.args...)
 
-	if len(cols) < 1 || len(args) < 1 {
+	cols, values, err := query.getUpdateColumnsAndValues()
+	if err != nil {
+		return err
+	}
+
+	if len(cols) < 1 || len(values) < 1 {
 		return errors.New("Columns or argument slice cannot be empty")
 	}
 
+	// Only the updated fields of the model are validated, update map values are validated against the rules of the matching fields
+	if query.updateValues == nil {
+		if err := query.modelPtr.Validate(cols...); err != nil {
+			return err
+		}
+	} else if query.modelPtr != nil {
+		if err := query.modelPtr.ValidateValues(validatedUpdateValues(cols, values)); err != nil {
+			return err
+		}
+	}
+
+	if query.modelPtr != nil && query.modelPtr.UpdatedAt != nil {
+		f := query.modelPtr.UpdatedAt
+		name := f.ColumnName()
+		if !containsString(cols, name) {
+			now := query.now()
+			if err := f.SetTime(now); err != nil {
+				return err
+			}
+
+			cols = append(cols, name)
+			values = append(values, now)
+		}
+	}
+
 	if query.useModelAsCond {
 		if err := query.addPKWhereConditions(); err != nil {
 			return err
 		}
 	}
 
+	// Every write increment the version, so the record modified by UpdateMap or UpdateWhere is stale for the loaded models
+	if query.modelPtr != nil && query.modelPtr.Version != nil {
+		name := query.modelPtr.Version.ColumnName()
+		if query.isVersionedUpdate() {
+			version, err := query.modelPtr.GetVersion()
+			if err != nil {
+				return err
+			}
+
+			query.whereConditions = append(query.whereConditions, IsEqualsTo(fmt.Sprintf("%s.%s", query.modelPtr.Name, name), version))
+		}
+
+		if !containsString(cols, name) {
+			cols = append(cols, name)
+			values = append(values, Add(Col(name), 1))
+		}
+	}
+
 	tableName, err := query.getTableName()
 	if err != nil {
 		return err
@@ -475,21 +1461,34 @@ func (q *Query) prepareUpdateQuery() error {
 
 	columnQuery := ""
 	valueQuery := ""
-	for i := 0; i < len(args); i++ {
+	for i := 0; i < len(values); i++ {
 		if i != 0 {
 			columnQuery += ","
 			valueQuery += ","
 		}
+
+		valueStr, valueArgs, err := operandToString(values[i], false)
+		if err != nil {
+			return err
+		}
+
+		query.args = append(query.args, valueArgs...)
 		columnQuery += cols[i]
-		valueQuery += "?"
+		valueQuery += valueStr
+	}
+
+	// Single column must not use row value, as PostgreSQL require ROW() or subquery as its source
+	setQuery := fmt.Sprintf("(%s) = (%s)", columnQuery, valueQuery)
+	if len(cols) == 1 {
+		setQuery = fmt.Sprintf("%s = %s", columnQuery, valueQuery)
 	}
 
-	whereQuery, err := query.prepareWhereQuery()
+	whereQuery, err := query.prepareFilterQuery("update")
 	if err != nil {
 		return err
 	}
 
-	query.SQL = fmt.Sprintf("UPDATE %s SET (%s) = (%s)%s;", tableName, columnQuery, valueQuery, whereQuery)
+	query.SQL = fmt.Sprintf("%sUPDATE %s SET %s%s%s;", withQuery, tableName, setQuery, whereQuery, query.prepareReturningQuery())
 	query.replaceSQLPlaceholder()
 
 	q.SQL = query.SQL
@@ -500,6 +1499,11 @@ func (q *Query) prepareUpdateQuery() error {
 
 func (q *Query) prepareDeleteQuery() error {
 	query := q.clone()
+	withQuery, err := query.prepareWithQuery()
+	if err != nil {
+		return err
+	}
+
 	if query.useModelAsCond {
 		if err := query.addPKWhereConditions(); err != nil {
 			return err
@@ -511,20 +1515,48 @@ func (q *Query) prepareDeleteQuery() error {
 		return err
 	}
 
-	if len(query.whereConditions) < 1 {
-		return errors.New("Unsupported delete without filter")
+	if query.isSoftDelete() {
+		return q.prepareSoftDeleteQuery(query, withQuery, tableName)
 	}
 
-	whereQuery, err := query.prepareWhereQuery()
+	whereQuery, err := query.prepareFilterQuery("delete")
 	if err != nil {
 		return err
 	}
 
-	query.SQL = fmt.Sprintf("DELETE FROM %s%s;", tableName, whereQuery)
+	query.SQL = fmt.Sprintf("%sDELETE FROM %s%s%s;", withQuery, tableName, whereQuery, query.prepareReturningQuery())
+	query.replaceSQLPlaceholder()
+
+	q.SQL = query.SQL
+	q.args = query.args
+
+	return nil
+}
+
+// Prepare UPDATE query setting the soft delete field, already soft deleted records are left untouched
+func (q *Query) prepareSoftDeleteQuery(query *Query, withQuery, tableName string) error {
+	deletedAt := query.now()
+	query.args = append(query.args, deletedAt)
+
+	whereQuery, err := query.prepareFilterQuery("delete")
+	if err != nil {
+		return err
+	}
+
+	column := query.getSoftDeleteColumn(tableName)
+	if whereQuery == "" {
+		whereQuery = fmt.Sprintf(" WHERE %s IS NULL", column)
+	} else {
+		whereQuery = fmt.Sprintf("%s AND %s IS NULL", whereQuery, column)
+	}
+
+	name := query.modelPtr.SoftDelete.ColumnName()
+	query.SQL = fmt.Sprintf("%sUPDATE %s SET %s = ?%s%s;", withQuery, tableName, name, whereQuery, query.prepareReturningQuery())
 	query.replaceSQLPlaceholder()
 
 	q.SQL = query.SQL
 	q.args = query.args
+	q.softDeletedAt = deletedAt
 
 	return nil
 }
@@ -1,8 +1,17 @@
 package fury
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/nandaryanizar/fury/model"
 )
 
 type User struct {
@@ -500,24 +509,529 @@ func TestPrepareUpdate(t *testing.T) {
 	}
 }
 
-func TestPrepareDelete(t *testing.T) {
+type Profile struct {
+	UserID   int    `fury:"primary_key"`
+	Email    string `fury:"omitempty"`
+	Nickname string `fury:"omitempty"`
+	Counter  int
+}
+
+func TestPrepareUpdatePartial(t *testing.T) {
+	cases := []struct {
+		have     interface{}
+		opts     []QueryOption
+		values   map[string]interface{}
+		want     string
+		wantArgs []interface{}
+	}{
+		{
+			&Profile{UserID: 1, Email: "some@test.com", Counter: 0},
+			nil,
+			nil,
+			"UPDATE profile SET (userid,email,counter) = ($1,$2,$3) WHERE profile.userid = $4;",
+			[]interface{}{1, "some@test.com", 0, 1},
+		},
+		{
+			&Profile{UserID: 1, Email: "some@test.com"},
+			[]QueryOption{Columns("nickname", "counter")},
+			nil,
+			"UPDATE profile SET (nickname,counter) = ($1,$2) WHERE profile.userid = $3;",
+			[]interface{}{"", 0, 1},
+		},
+		{
+			&Profile{UserID: 1, Email: "some@test.com", Nickname: "some"},
+			[]QueryOption{Omit("userid", "counter", "nickname")},
+			nil,
+			"UPDATE profile SET email = $1 WHERE profile.userid = $2;",
+			[]interface{}{"some@test.com", 1},
+		},
+		{
+			&Profile{UserID: 1},
+			nil,
+			map[string]interface{}{"nickname": "some", "counter": Add(Col("counter"), 1)},
+			"UPDATE profile SET (counter,nickname) = ((counter + $1),$2) WHERE profile.userid = $3;",
+			[]interface{}{1, "some", 1},
+		},
+	}
+
+	for _, tc := range cases {
+		q := mustNewQuery(t, tc.have, tc.opts...)
+		q.updateValues = tc.values
+
+		if err := q.prepareUpdateQuery(); err != nil {
+			t.Error(err)
+		}
+
+		if tc.want != q.SQL || !reflect.DeepEqual(tc.wantArgs, q.args) {
+			t.Errorf("Error: expected %s and %v, found %s and %v", tc.want, tc.wantArgs, q.SQL, q.args)
+		}
+	}
+}
+
+func TestPrepareUpdatePartialErrors(t *testing.T) {
 	cases := []struct {
 		have interface{}
-		want string
+		opts []QueryOption
+	}{
+		{&Profile{UserID: 1}, []QueryOption{Columns("unknown")}},
+		{&Profile{UserID: 1}, []QueryOption{Omit("unknown")}},
+		{&Profile{}, []QueryOption{Omit("counter")}},
+	}
+
+	for _, tc := range cases {
+		q := mustNewQuery(t, tc.have, tc.opts...)
+		if err := q.prepareUpdateQuery(); err == nil {
+			t.Error("Expected error found nil")
+		}
+	}
+}
+
+func TestPrepareSetBasedQuery(t *testing.T) {
+	cases := []struct {
+		opts     []QueryOption
+		values   Set
+		want     string
+		wantArgs []interface{}
 	}{
 		{
-			&User{UserID: 123, Counter: 1},
-			"DELETE FROM user WHERE user.userid = $1;",
+			[]QueryOption{Where(IsEqualsTo("status", "active")), Where(IsLessThan("lastlogin", "2019-01-01"))},
+			Set{"nickname": "inactive"},
+			"UPDATE profile SET nickname = $1 WHERE status = $2 AND lastlogin < $3;",
+			[]interface{}{"inactive", "active", "2019-01-01"},
+		},
+		{
+			[]QueryOption{AllowFullTable()},
+			Set{"counter": 0},
+			"UPDATE profile SET counter = $1;",
+			[]interface{}{0},
+		},
+		{
+			[]QueryOption{Where(IsEqualsTo("status", "inactive"))},
+			nil,
+			"DELETE FROM profile WHERE status = $1;",
+			[]interface{}{"inactive"},
+		},
+		{
+			[]QueryOption{AllowFullTable()},
+			nil,
+			"DELETE FROM profile;",
+			nil,
 		},
 	}
 
 	for _, tc := range cases {
-		q, err := NewQuery(tc.have)
+		q := mustNewQuery(t, &Profile{}, tc.opts...)
+
+		var err error
+		if tc.values != nil {
+			q.updateValues = tc.values
+			err = q.prepareUpdateQuery()
+		} else {
+			err = q.prepareDeleteQuery()
+		}
+
 		if err != nil {
 			t.Error(err)
 		}
 
-		if err := q.prepareDeleteQuery(); err != nil {
+		if tc.want != q.SQL || (len(tc.wantArgs) > 0 && !reflect.DeepEqual(tc.wantArgs, q.args)) {
+			t.Errorf("Error: expected %s and %v, found %s and %v", tc.want, tc.wantArgs, q.SQL, q.args)
+		}
+	}
+
+	q := mustNewQuery(t, &Profile{})
+	q.updateValues = Set{"nickname": "inactive"}
+	if err := q.prepareUpdateQuery(); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	// Key without matching field is rejected instead of written as column name
+	for _, values := range []Set{{"status": "inactive"}, {"email = 'x', counter": 1}} {
+		q := mustNewQuery(t, &Profile{}, AllowFullTable())
+		q.updateValues = values
+		if err := q.prepareUpdateQuery(); err == nil {
+			t.Errorf("Error: expected error, found %s", q.SQL)
+		}
+	}
+
+	if err := q.prepareDeleteQuery(); err == nil {
+		t.Error("Expected error found nil")
+	}
+}
+
+func TestPrepareReturningQuery(t *testing.T) {
+	q := mustNewQuery(t, &Profile{UserID: 1}, Columns("counter"), Returning("counter", "nickname"))
+	if err := q.prepareUpdateQuery(); err != nil {
+		t.Error(err)
+	}
+
+	want := "UPDATE profile SET counter = $1 WHERE profile.userid = $2 RETURNING counter, nickname;"
+	if q.SQL != want {
+		t.Errorf("Error: expected %s, found %s", want, q.SQL)
+	}
+
+	q = mustNewQuery(t, &Profile{UserID: 1}, Returning("*"))
+	if err := q.prepareDeleteQuery(); err != nil {
+		t.Error(err)
+	}
+
+	want = "DELETE FROM profile WHERE profile.userid = $1 RETURNING *;"
+	if q.SQL != want {
+		t.Errorf("Error: expected %s, found %s", want, q.SQL)
+	}
+
+	q = mustNewQuery(t, &User{UserID: 1}, Returning("counter"))
+	if err := q.prepareInsertQuery(); err != nil {
+		t.Error(err)
+	}
+
+	want = "INSERT INTO user(userid) VALUES($1) RETURNING counter;"
+	if q.SQL != want {
+		t.Errorf("Error: expected %s, found %s", want, q.SQL)
+	}
+
+	if _, err := NewQuery(&Profile{}, Returning()); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	if _, err := NewQuery(&Profile{}, RowsAffected(nil)); err == nil {
+		t.Error("Expected error found nil")
+	}
+}
+
+type TrackedProfile struct {
+	model.Snapshot
+	UserID   int `fury:"primary_key"`
+	Email    string
+	Status   string
+	Settings map[string]string
+}
+
+type recordingConnectionPool struct {
+	mockConnectionPool
+	queries  []string
+	affected int64
+}
+
+func (rcp *recordingConnectionPool) Exec(query string, args ...interface{}) (sql.Result, error) {
+	rcp.queries = append(rcp.queries, query)
+	return driver.RowsAffected(rcp.affected), nil
+}
+
+// resultDriver is database/sql connector returning the configured rows to every query, so scanning can be tested without database
+type resultDriver struct {
+	queries  []string
+	columns  []string
+	rows     [][]driver.Value
+	affected int64
+}
+
+// Create DB using resultDriver as connection pool, every query return the columns and rows
+func newResultDB(columns []string, rows ...[]driver.Value) (*DB, *resultDriver) {
+	d := &resultDriver{columns: columns, rows: rows, affected: int64(len(rows))}
+	db, _ := ConnectMock(sql.OpenDB(d))
+	return db, d
+}
+
+func (d *resultDriver) Connect(ctx context.Context) (driver.Conn, error) { return &resultConn{d}, nil }
+
+func (d *resultDriver) Driver() driver.Driver { return d }
+
+func (d *resultDriver) Open(name string) (driver.Conn, error) { return &resultConn{d}, nil }
+
+type resultConn struct {
+	d *resultDriver
+}
+
+func (c *resultConn) Prepare(query string) (driver.Stmt, error) { return &resultStmt{c.d, query}, nil }
+
+func (c *resultConn) Close() error { return nil }
+
+func (c *resultConn) Begin() (driver.Tx, error) { return c, nil }
+
+func (c *resultConn) Commit() error { return nil }
+
+func (c *resultConn) Rollback() error { return nil }
+
+type resultStmt struct {
+	d     *resultDriver
+	query string
+}
+
+func (s *resultStmt) Close() error { return nil }
+
+func (s *resultStmt) NumInput() int { return -1 }
+
+func (s *resultStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.queries = append(s.d.queries, s.query)
+	return driver.RowsAffected(s.d.affected), nil
+}
+
+func (s *resultStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.queries = append(s.d.queries, s.query)
+
+	// COUNT query return the number of rows instead
+	if strings.HasPrefix(s.query, "SELECT COUNT(*)") {
+		return &resultRows{columns: []string{"count"}, rows: [][]driver.Value{{int64(len(s.d.rows))}}}, nil
+	}
+
+	return &resultRows{columns: s.d.columns, rows: s.d.rows}, nil
+}
+
+type resultRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *resultRows) Columns() []string { return r.columns }
+
+func (r *resultRows) Close() error { return nil }
+
+func (r *resultRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestInsertReturning(t *testing.T) {
+	db, d := newResultDB([]string{"counter"}, []driver.Value{int64(7)})
+
+	users := []*User{&User{UserID: 1}, &User{UserID: 2}}
+	var affected int64
+	if err := db.Insert(&users, Returning("counter"), RowsAffected(&affected)); err != nil {
+		t.Fatal(err)
+	}
+
+	if affected != 2 || users[0].Counter != 7 || users[1].Counter != 7 {
+		t.Errorf("Error: expected %v rows with counter %v, found %v and %v", 2, 7, affected, users)
+	}
+
+	want := []string{
+		"INSERT INTO user(userid) VALUES($1) RETURNING counter;",
+		"INSERT INTO user(userid) VALUES($1) RETURNING counter;",
+	}
+	if !reflect.DeepEqual(want, d.queries) {
+		t.Errorf("Error: expected %v, found %v", want, d.queries)
+	}
+}
+
+func TestUpdateDirtyTracking(t *testing.T) {
+	pool := &recordingConnectionPool{affected: 1}
+	db, err := ConnectMock(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profiles := []*TrackedProfile{
+		&TrackedProfile{UserID: 1, Email: "a@test.com", Status: "active", Settings: map[string]string{"theme": "light"}},
+		&TrackedProfile{UserID: 2, Email: "b@test.com", Status: "active"},
+	}
+
+	models, _, err := model.NewModels(profiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, m := range models {
+		m.TakeSnapshot()
+	}
+
+	profiles[1].Status = "inactive"
+
+	var affected int64
+	if err := db.Update(profiles, RowsAffected(&affected)); err != nil {
+		t.Error(err)
+	}
+
+	if affected != 1 {
+		t.Errorf("Error: expected %v, found %v", 1, affected)
+	}
+
+	if err := db.Update(profiles, RowsAffected(&affected)); err != nil {
+		t.Error(err)
+	}
+
+	if affected != 0 {
+		t.Errorf("Error: expected %v, found %v", 0, affected)
+	}
+
+	// Map modified in place is detected as the snapshot hold its copy
+	profiles[0].Settings["theme"] = "dark"
+	if err := db.Update(profiles, RowsAffected(&affected)); err != nil {
+		t.Error(err)
+	}
+
+	if affected != 1 {
+		t.Errorf("Error: expected %v, found %v", 1, affected)
+	}
+
+	want := []string{
+		"UPDATE trackedprofile SET status = $1 WHERE trackedprofile.userid = $2;",
+		"UPDATE trackedprofile SET settings = $1 WHERE trackedprofile.userid = $2;",
+	}
+	if !reflect.DeepEqual(want, pool.queries) {
+		t.Errorf("Error: expected %v, found %v", want, pool.queries)
+	}
+}
+
+type VersionedProfile struct {
+	UserID  int `fury:"primary_key"`
+	Email   string
+	Version int `fury:"version"`
+}
+
+type TrackedTimestampProfile struct {
+	model.Snapshot
+	UserID    int `fury:"primary_key"`
+	Email     string
+	Nickname  string    `fury:"omitempty"`
+	CreatedAt time.Time `fury:"created_at"`
+}
+
+func TestUpdateDirtyTrackingWithoutColumns(t *testing.T) {
+	pool := &recordingConnectionPool{affected: 1}
+	db, err := ConnectMock(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		modify func(p *TrackedTimestampProfile)
+		opts   []QueryOption
+	}{
+		{func(p *TrackedTimestampProfile) { p.Email = "b@test.com" }, []QueryOption{Omit("email")}},
+		{func(p *TrackedTimestampProfile) { p.Nickname = "" }, nil},
+		{func(p *TrackedTimestampProfile) { p.CreatedAt = p.CreatedAt.Add(time.Hour) }, nil},
+	}
+
+	for _, tc := range cases {
+		profile := &TrackedTimestampProfile{UserID: 1, Email: "a@test.com", Nickname: "a", CreatedAt: time.Now()}
+		_, m, err := model.NewModels(profile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.TakeSnapshot()
+
+		tc.modify(profile)
+
+		var affected int64
+		if err := db.Update(profile, append(tc.opts, RowsAffected(&affected))...); err != nil {
+			t.Error(err)
+		}
+
+		if affected != 0 {
+			t.Errorf("Error: expected %v, found %v", 0, affected)
+		}
+	}
+
+	if len(pool.queries) != 0 {
+		t.Errorf("Error: expected no query, found %v", pool.queries)
+	}
+}
+
+func TestPrepareVersionedUpdate(t *testing.T) {
+	q := mustNewQuery(t, &VersionedProfile{UserID: 1, Email: "some@test.com", Version: 3})
+	if err := q.prepareUpdateQuery(); err != nil {
+		t.Error(err)
+	}
+
+	want := "UPDATE versionedprofile SET (userid,email,version) = ($1,$2,(version + $3)) WHERE versionedprofile.userid = $4 AND versionedprofile.version = $5;"
+	wantArgs := []interface{}{1, "some@test.com", 1, 1, int64(3)}
+	if q.SQL != want || !reflect.DeepEqual(q.args, wantArgs) {
+		t.Errorf("Error: expected %s and %v, found %s and %v", want, wantArgs, q.SQL, q.args)
+	}
+}
+
+func TestPrepareVersionedUpdateValues(t *testing.T) {
+	pool := &recordingConnectionPool{}
+	db, err := ConnectMock(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Version is incremented without being checked, so no record updated is not an error
+	profile := &VersionedProfile{UserID: 1, Version: 3}
+	if err := db.UpdateMap(profile, map[string]interface{}{"email": "some@test.com"}); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := db.UpdateWhere(&VersionedProfile{}, Set{"email": "some@test.com"}, Where(IsEqualsTo("userid", 2))); err != nil {
+		t.Error(err)
+	}
+
+	// Version set explicitly is not incremented
+	if _, err := db.UpdateWhere(&VersionedProfile{}, Set{"version": 0}, AllowFullTable()); err != nil {
+		t.Error(err)
+	}
+
+	want := []string{
+		"UPDATE versionedprofile SET (email,version) = ($1,(version + $2)) WHERE versionedprofile.userid = $3;",
+		"UPDATE versionedprofile SET (email,version) = ($1,(version + $2)) WHERE userid = $3;",
+		"UPDATE versionedprofile SET version = $1;",
+	}
+	if !reflect.DeepEqual(want, pool.queries) {
+		t.Errorf("Error: expected %v, found %v", want, pool.queries)
+	}
+
+	if profile.Version != 3 {
+		t.Errorf("Error: expected %v, found %v", 3, profile.Version)
+	}
+}
+
+func TestUpdateOptimisticLocking(t *testing.T) {
+	pool := &recordingConnectionPool{affected: 1}
+	db, err := ConnectMock(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profile := &VersionedProfile{UserID: 1, Email: "some@test.com", Version: 3}
+	if err := db.Update(profile); err != nil {
+		t.Error(err)
+	}
+
+	if profile.Version != 4 {
+		t.Errorf("Error: expected %v, found %v", 4, profile.Version)
+	}
+
+	pool.affected = 0
+	if err := db.Update(profile); err != ErrStaleObject {
+		t.Errorf("Error: expected %v, found %v", ErrStaleObject, err)
+	}
+
+	if profile.Version != 4 {
+		t.Errorf("Error: expected %v, found %v", 4, profile.Version)
+	}
+}
+
+type SoftProfile struct {
+	UserID    int `fury:"primary_key"`
+	Email     string
+	DeletedAt *time.Time `fury:"soft_delete"`
+}
+
+func TestPrepareSoftDeleteSelect(t *testing.T) {
+	cases := []struct {
+		have interface{}
+		opts []QueryOption
+		want string
+	}{
+		{&[]*SoftProfile{}, nil, "SELECT * FROM softprofile WHERE softprofile.deletedat IS NULL;"},
+		{&SoftProfile{UserID: 1}, nil, "SELECT * FROM softprofile WHERE softprofile.userid = $1 AND softprofile.deletedat IS NULL;"},
+		{&[]*SoftProfile{}, []QueryOption{Alias("s"), Where(IsEqualsTo("s.email", "a"))}, "SELECT * FROM softprofile AS s WHERE s.email = $1 AND s.deletedat IS NULL;"},
+		{&[]*SoftProfile{}, []QueryOption{Table("softprofile AS p"), Where(IsEqualsTo("p.email", "a"))}, "SELECT * FROM softprofile AS p WHERE p.email = $1 AND p.deletedat IS NULL;"},
+		{&[]*SoftProfile{}, []QueryOption{Table("softprofile p")}, "SELECT * FROM softprofile p WHERE p.deletedat IS NULL;"},
+		{&[]*SoftProfile{}, []QueryOption{WithDeleted()}, "SELECT * FROM softprofile;"},
+		{&[]*SoftProfile{}, []QueryOption{Unscoped()}, "SELECT * FROM softprofile;"},
+	}
+
+	for _, tc := range cases {
+		q := mustNewQuery(t, tc.have, tc.opts...)
+		if err := q.prepareSelectQuery(); err != nil {
 			t.Error(err)
 		}
 
@@ -525,4 +1039,957 @@ func TestPrepareDelete(t *testing.T) {
 			t.Errorf("Error: expected %s, found %s", tc.want, q.SQL)
 		}
 	}
+
+	q := mustNewQuery(t, &SoftProfile{})
+	if err := q.prepareCountQuery(); err != nil {
+		t.Error(err)
+	}
+
+	want := "SELECT COUNT(*) FROM softprofile WHERE softprofile.deletedat IS NULL;"
+	if want != q.SQL {
+		t.Errorf("Error: expected %s, found %s", want, q.SQL)
+	}
+}
+
+func TestPrepareSoftDelete(t *testing.T) {
+	cases := []struct {
+		have     interface{}
+		opts     []QueryOption
+		want     string
+		wantArgs int
+	}{
+		{&SoftProfile{UserID: 1}, nil, "UPDATE softprofile SET deletedat = $1 WHERE softprofile.userid = $2 AND softprofile.deletedat IS NULL;", 2},
+		{&SoftProfile{}, []QueryOption{AllowFullTable()}, "UPDATE softprofile SET deletedat = $1 WHERE softprofile.deletedat IS NULL;", 1},
+		{&SoftProfile{}, []QueryOption{Table("softprofile AS p"), Where(IsEqualsTo("p.email", "a"))}, "UPDATE softprofile AS p SET deletedat = $1 WHERE p.email = $2 AND p.deletedat IS NULL;", 2},
+		{&SoftProfile{UserID: 1}, []QueryOption{HardDelete()}, "DELETE FROM softprofile WHERE softprofile.userid = $1;", 1},
+		{&SoftProfile{UserID: 1}, []QueryOption{Unscoped()}, "DELETE FROM softprofile WHERE softprofile.userid = $1;", 1},
+	}
+
+	for _, tc := range cases {
+		q := mustNewQuery(t, tc.have, tc.opts...)
+		if err := q.prepareDeleteQuery(); err != nil {
+			t.Error(err)
+		}
+
+		if tc.want != q.SQL || tc.wantArgs != len(q.args) {
+			t.Errorf("Error: expected %s with %d arguments, found %s with %v", tc.want, tc.wantArgs, q.SQL, q.args)
+		}
+	}
+
+	q := mustNewQuery(t, &SoftProfile{})
+	if err := q.prepareDeleteQuery(); err == nil {
+		t.Error("Expected error found nil")
+	}
+}
+
+func TestSoftDeleteSetField(t *testing.T) {
+	db, err := ConnectMock(&recordingConnectionPool{affected: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profile := &SoftProfile{UserID: 1}
+	if err := db.Delete(profile); err != nil {
+		t.Error(err)
+	}
+
+	if profile.DeletedAt == nil || profile.DeletedAt.IsZero() {
+		t.Errorf("Error: expected deletion time, found %v", profile.DeletedAt)
+	}
+}
+
+type TimestampProfile struct {
+	UserID    int `fury:"primary_key"`
+	Email     string
+	CreatedAt time.Time  `fury:"created_at"`
+	UpdatedAt *time.Time `fury:"updated_at"`
+}
+
+func TestSetClockTransaction(t *testing.T) {
+	parentNow := time.Date(2019, 6, 28, 2, 26, 0, 0, time.UTC)
+	txNow := parentNow.Add(time.Hour)
+
+	db, _ := newResultDB(nil)
+	db.SetClock(func() time.Time { return parentNow })
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	tx.SetClock(func() time.Time { return txNow })
+
+	if now := db.config.Now(); !now.Equal(parentNow) {
+		t.Errorf("Error: expected %v, found %v", parentNow, now)
+	}
+
+	if now := tx.config.Now(); !now.Equal(txNow) {
+		t.Errorf("Error: expected %v, found %v", txNow, now)
+	}
+}
+
+func TestAutomaticTimestamps(t *testing.T) {
+	now := time.Date(2019, 6, 28, 2, 26, 0, 0, time.UTC)
+	pool := &recordingConnectionPool{affected: 1}
+	db, err := ConnectMock(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetClock(func() time.Time { return now })
+
+	profile := &TimestampProfile{UserID: 1, Email: "some@test.com"}
+	if err := db.Insert(profile); err != nil {
+		t.Error(err)
+	}
+
+	if !profile.CreatedAt.Equal(now) || profile.UpdatedAt == nil || !profile.UpdatedAt.Equal(now) {
+		t.Errorf("Error: expected %v, found %v and %v", now, profile.CreatedAt, profile.UpdatedAt)
+	}
+
+	now = now.Add(time.Hour)
+	if err := db.Update(profile); err != nil {
+		t.Error(err)
+	}
+
+	if !profile.CreatedAt.Equal(now.Add(-time.Hour)) || !profile.UpdatedAt.Equal(now) {
+		t.Errorf("Error: expected %v and %v, found %v and %v", now.Add(-time.Hour), now, profile.CreatedAt, profile.UpdatedAt)
+	}
+
+	if err := db.UpdateMap(profile, map[string]interface{}{"email": "other@test.com"}); err != nil {
+		t.Error(err)
+	}
+
+	want := []string{
+		"INSERT INTO timestampprofile(userid,email,createdat,updatedat) VALUES($1,$2,$3,$4);",
+		"UPDATE timestampprofile SET (userid,email,updatedat) = ($1,$2,$3) WHERE timestampprofile.userid = $4;",
+		"UPDATE timestampprofile SET (email,updatedat) = ($1,$2) WHERE timestampprofile.userid = $3;",
+	}
+	if !reflect.DeepEqual(want, pool.queries) {
+		t.Errorf("Error: expected %v, found %v", want, pool.queries)
+	}
+}
+
+func TestPrepareDelete(t *testing.T) {
+	cases := []struct {
+		have interface{}
+		want string
+	}{
+		{
+			&User{UserID: 123, Counter: 1},
+			"DELETE FROM user WHERE user.userid = $1;",
+		},
+	}
+
+	for _, tc := range cases {
+		q, err := NewQuery(tc.have)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if err := q.prepareDeleteQuery(); err != nil {
+			t.Error(err)
+		}
+
+		if tc.want != q.SQL {
+			t.Errorf("Error: expected %s, found %s", tc.want, q.SQL)
+		}
+	}
+}
+
+func TestPrepareSelectWithoutConditions(t *testing.T) {
+	q, err := NewQuery(&[]*User{})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if err := q.prepareSelectQuery(); err != nil {
+		t.Error(err)
+	}
+
+	want := "SELECT * FROM user;"
+	if want != q.SQL {
+		t.Errorf("Error: expected %s, found %s", want, q.SQL)
+	}
+}
+
+func TestPrepareCount(t *testing.T) {
+	cases := []struct {
+		have *Query
+		want string
+	}{
+		{
+			&Query{
+				tableName:       "user",
+				whereConditions: []interface{}{IsGreaterThan("user.counter", 1)},
+				orders:          []interface{}{"user.counter DESC"},
+			},
+			"SELECT COUNT(*) FROM user WHERE user.counter > $1;",
+		},
+		{
+			&Query{
+				tableName: "user",
+				columns:   []interface{}{"user.counter"},
+				groups:    []interface{}{"user.counter"},
+				limit:     10,
+			},
+			"SELECT COUNT(*) FROM (SELECT user.counter FROM user GROUP BY user.counter LIMIT 10) AS count_query;",
+		},
+	}
+
+	for _, tc := range cases {
+		if err := tc.have.prepareCountQuery(); err != nil {
+			t.Error(err)
+		}
+
+		if tc.want != tc.have.SQL {
+			t.Errorf("Error: expected %s, found %s", tc.want, tc.have.SQL)
+		}
+	}
+}
+
+func TestPrepareExists(t *testing.T) {
+	q, err := NewQuery(&User{UserID: 2})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if err := q.prepareExistsQuery(); err != nil {
+		t.Error(err)
+	}
+
+	want := "SELECT EXISTS(SELECT 1 FROM user WHERE user.userid = $1 LIMIT 1);"
+	if want != q.SQL {
+		t.Errorf("Error: expected %s, found %s", want, q.SQL)
+	}
+}
+
+func TestPrepareAggregate(t *testing.T) {
+	cases := []struct {
+		function string
+		column   string
+		want     string
+	}{
+		{"SUM", "user.counter", "SELECT SUM(user.counter) FROM user WHERE user.counter > $1;"},
+		{"MAX", "user.counter", "SELECT MAX(user.counter) FROM user WHERE user.counter > $1;"},
+	}
+
+	for _, tc := range cases {
+		q := &Query{
+			tableName:       "user",
+			whereConditions: []interface{}{IsGreaterThan("user.counter", 1)},
+			limit:           1,
+		}
+
+		if err := q.prepareAggregateQuery(tc.function, tc.column); err != nil {
+			t.Error(err)
+		}
+
+		if tc.want != q.SQL {
+			t.Errorf("Error: expected %s, found %s", tc.want, q.SQL)
+		}
+	}
+
+	if err := (&Query{tableName: "user"}).prepareAggregateQuery("SUM", ""); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	if err := (&Query{tableName: "user", groups: []interface{}{"user.counter"}}).prepareAggregateQuery("SUM", "user.counter"); err == nil {
+		t.Error("Expected error found nil")
+	}
+}
+
+func TestNewQueryDestination(t *testing.T) {
+	cases := []struct {
+		have interface{}
+		want destinationType
+	}{
+		{&User{}, modelDestination},
+		{&[]*User{}, modelDestination},
+		{&map[string]interface{}{}, mapDestination},
+		{&[]map[string]interface{}{}, mapDestination},
+	}
+
+	for _, tc := range cases {
+		q, err := NewQuery(tc.have)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if q.destination != tc.want {
+			t.Errorf("Error: expected %v, found %v", tc.want, q.destination)
+		}
+	}
+}
+
+func TestNewScalarQuery(t *testing.T) {
+	cases := []struct {
+		have      interface{}
+		column    string
+		wantError bool
+	}{
+		{&[]string{}, "email", false},
+		{&[]int{}, "", true},
+		{[]string{}, "email", true},
+		{new(string), "email", true},
+	}
+
+	for _, tc := range cases {
+		q, err := newScalarQuery(tc.have, tc.column)
+		if tc.wantError {
+			if err == nil {
+				t.Error("Expected error found nil")
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		q.tableName = "user"
+		if err := q.prepareSelectQuery(); err != nil {
+			t.Error(err)
+		}
+
+		want := "SELECT email FROM user;"
+		if q.SQL != want {
+			t.Errorf("Error: expected %s, found %s", want, q.SQL)
+		}
+	}
+}
+
+func TestPrepareSelectJoin(t *testing.T) {
+	cases := []struct {
+		opts     []QueryOption
+		want     string
+		wantArgs []interface{}
+	}{
+		{
+			[]QueryOption{
+				Alias("u"),
+				Join("orders AS o", IsEqualsTo("o.userid", Col("u.userid"))),
+				Where(IsGreaterThan("o.total", 100)),
+			},
+			"SELECT * FROM user AS u INNER JOIN orders AS o ON o.userid = u.userid WHERE o.total > $1 AND u.userid = $2;",
+			[]interface{}{100, 2},
+		},
+		{
+			[]QueryOption{
+				LeftJoin("orders", And(IsEqualsTo("orders.userid", Col("user.userid")), IsEqualsTo("orders.status", "paid"))),
+				RightJoin("payment", "payment.orderid = orders.orderid"),
+				FullJoin("refund", IsEqualsTo("refund.orderid", Col("orders.orderid"))),
+			},
+			"SELECT * FROM user LEFT JOIN orders ON (orders.userid = user.userid AND orders.status = $1) RIGHT JOIN payment ON payment.orderid = orders.orderid FULL JOIN refund ON refund.orderid = orders.orderid WHERE user.userid = $2;",
+			[]interface{}{"paid", 2},
+		},
+	}
+
+	for _, tc := range cases {
+		q, err := NewQuery(&User{UserID: 2})
+		if err != nil {
+			t.Error(err)
+		}
+
+		for _, opt := range tc.opts {
+			if _, err := opt(q); err != nil {
+				t.Error(err)
+			}
+		}
+
+		if err := q.prepareSelectQuery(); err != nil {
+			t.Error(err)
+		}
+
+		if tc.want != q.SQL || !reflect.DeepEqual(tc.wantArgs, q.args) {
+			t.Errorf("Error: expected %s and %v, found %s and %v", tc.want, tc.wantArgs, q.SQL, q.args)
+		}
+	}
+}
+
+func TestUnsupportedJoinConditions(t *testing.T) {
+	cases := []QueryOption{
+		Join("orders", 1),
+		LeftJoin("", IsEqualsTo("a", Col("b"))),
+		Alias(""),
+	}
+
+	for _, opt := range cases {
+		if _, err := opt(&Query{}); err == nil {
+			t.Error("Expected error found nil")
+		}
+	}
+}
+
+func TestPrepareSelectHavingDistinct(t *testing.T) {
+	cases := []struct {
+		opts     []QueryOption
+		want     string
+		wantArgs []interface{}
+	}{
+		{
+			[]QueryOption{
+				Select("user.counter", "COUNT(*)"),
+				Where(IsGreaterThan("user.userid", 1)),
+				GroupBy("user.counter"),
+				Having(IsGreaterThan(Func("COUNT", Col("*")), 2)),
+				Having("SUM(user.userid) > 10"),
+			},
+			"SELECT user.counter, COUNT(*) FROM user WHERE user.userid > $1 GROUP BY user.counter HAVING COUNT(*) > $2 AND SUM(user.userid) > 10;",
+			[]interface{}{1, 2},
+		},
+		{
+			[]QueryOption{Distinct(), Select("user.counter")},
+			"SELECT DISTINCT user.counter FROM user;",
+			nil,
+		},
+		{
+			[]QueryOption{DistinctOn("user.counter"), OrderBy("user.counter", "user.userid DESC")},
+			"SELECT DISTINCT ON (user.counter) * FROM user ORDER BY user.counter, user.userid DESC;",
+			nil,
+		},
+	}
+
+	for _, tc := range cases {
+		q := &Query{tableName: "user"}
+
+		for _, opt := range tc.opts {
+			if _, err := opt(q); err != nil {
+				t.Error(err)
+			}
+		}
+
+		if err := q.prepareSelectQuery(); err != nil {
+			t.Error(err)
+		}
+
+		if tc.want != q.SQL || !reflect.DeepEqual(tc.wantArgs, q.args) {
+			t.Errorf("Error: expected %s and %v, found %s and %v", tc.want, tc.wantArgs, q.SQL, q.args)
+		}
+	}
+}
+
+func TestUnsupportedHavingConditions(t *testing.T) {
+	cases := []QueryOption{
+		Having(1),
+		DistinctOn(),
+	}
+
+	for _, opt := range cases {
+		if _, err := opt(&Query{}); err == nil {
+			t.Error("Expected error found nil")
+		}
+	}
+}
+
+type Orders struct {
+	OrderID int `fury:"primary_key"`
+	OwnerID int
+	Total   int
+}
+
+type UserOrder struct {
+	UserID int
+	Order  *Orders
+}
+
+func TestScanLeftJoinNestedStruct(t *testing.T) {
+	db, _ := newResultDB(
+		[]string{"userid", "order.orderid", "order.total"},
+		[]driver.Value{int64(1), int64(10), int64(100)},
+		[]driver.Value{int64(2), nil, nil},
+	)
+
+	have := []*UserOrder{}
+	err := db.Find(&have,
+		Table("user AS u"),
+		LeftJoin("orders AS o", IsEqualsTo("o.ownerid", Col("u.userid"))),
+		Select("u.userid", `o.orderid AS "order.orderid"`, `o.total AS "order.total"`),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(have) != 2 || have[0].Order == nil || have[0].Order.Total != 100 || have[1].Order != nil {
+		t.Errorf("Error: expected matched order of user 1 and nil order of user 2, found %v", have)
+	}
+}
+
+func TestPrepareSelectSubquery(t *testing.T) {
+	sub, err := NewQuery(&Orders{}, Select("orders.ownerid"), Where(IsGreaterThan("orders.total", 100)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	derived, err := NewQuery(&Orders{}, Select("orders.ownerid", "SUM(orders.total) AS total"), Where(IsGreaterThan("orders.total", 50)), GroupBy("orders.ownerid"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		have     interface{}
+		opts     []QueryOption
+		want     string
+		wantArgs []interface{}
+	}{
+		{
+			&User{},
+			[]QueryOption{Where(IsEqualsTo("user.counter", 1)), Where(IsIn("user.userid", sub))},
+			"SELECT * FROM user WHERE user.counter = $1 AND user.userid IN (SELECT orders.ownerid FROM orders WHERE orders.total > $2);",
+			[]interface{}{1, 100},
+		},
+		{
+			&User{UserID: 3},
+			[]QueryOption{Where(Exists(sub)), Where(NotExists(sub))},
+			"SELECT * FROM user WHERE EXISTS (SELECT orders.ownerid FROM orders WHERE orders.total > $1) AND NOT EXISTS (SELECT orders.ownerid FROM orders WHERE orders.total > $2) AND user.userid = $3;",
+			[]interface{}{100, 100, 3},
+		},
+		{
+			&[]map[string]interface{}{},
+			[]QueryOption{Table(derived), Alias("t"), Where(IsGreaterThan("t.total", 1))},
+			"SELECT * FROM (SELECT orders.ownerid, SUM(orders.total) AS total FROM orders WHERE orders.total > $1 GROUP BY orders.ownerid) AS t WHERE t.total > $2;",
+			[]interface{}{50, 1},
+		},
+	}
+
+	for _, tc := range cases {
+		q, err := NewQuery(tc.have, tc.opts...)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if err := q.prepareSelectQuery(); err != nil {
+			t.Error(err)
+		}
+
+		if tc.want != q.SQL || !reflect.DeepEqual(tc.wantArgs, q.args) {
+			t.Errorf("Error: expected %s and %v, found %s and %v", tc.want, tc.wantArgs, q.SQL, q.args)
+		}
+	}
+}
+
+func TestSubqueryConcurrentToString(t *testing.T) {
+	// Three conditions leave spare capacity in the condition slice, so appending to the shared slice would race
+	sub := mustNewQuery(t, &SoftProfile{UserID: 1}, Select("softprofile.userid"),
+		Where(IsGreaterThan("softprofile.userid", 0)), Where(IsLessThan("softprofile.userid", 10)), Where(IsNotEqualsTo("softprofile.email", "")))
+	want := "(SELECT softprofile.userid FROM softprofile WHERE softprofile.userid > ? AND softprofile.userid < ? AND softprofile.email <> ? AND softprofile.userid = ? AND softprofile.deletedat IS NULL)"
+
+	var wg sync.WaitGroup
+	results := make(chan string, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			str, _, err := sub.ToString()
+			if err != nil {
+				t.Error(err)
+			}
+			results <- str
+		}()
+	}
+
+	wg.Wait()
+	close(results)
+	for str := range results {
+		if str != want {
+			t.Errorf("Error: expected %s, found %s", want, str)
+		}
+	}
+}
+
+func TestDerivedTableErrors(t *testing.T) {
+	sub, err := NewQuery(&Orders{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := NewQuery(&Orders{}, Table(sub))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := q.prepareSelectQuery(); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	if err := q.prepareDeleteQuery(); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	if _, err := Table(1)(&Query{}); err == nil {
+		t.Error("Expected error found nil")
+	}
+}
+
+func TestPrepareWithQuery(t *testing.T) {
+	big, err := NewQuery(&Orders{}, Select("orders.ownerid"), Where(IsGreaterThan("orders.total", 100)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tree := "SELECT userid, counter FROM user WHERE userid = 1 UNION ALL SELECT u.userid, u.counter FROM user AS u INNER JOIN tree AS t ON u.counter = t.userid"
+
+	cases := []struct {
+		opts     []QueryOption
+		prepare  func(q *Query) error
+		want     string
+		wantArgs []interface{}
+	}{
+		{
+			[]QueryOption{With("big", big), Where(IsIn("user.userid", mustNewQuery(t, &[]map[string]interface{}{}, Table("big"), Select("ownerid")))), Where(IsEqualsTo("user.counter", 2))},
+			(*Query).prepareSelectQuery,
+			"WITH big AS (SELECT orders.ownerid FROM orders WHERE orders.total > $1) SELECT * FROM user WHERE user.userid IN (SELECT ownerid FROM big) AND user.counter = $2 AND user.userid = $3;",
+			[]interface{}{100, 2, 3},
+		},
+		{
+			[]QueryOption{WithRecursive("tree(userid, counter)", tree), With("big", big), Where(IsIn("user.userid", Col("(SELECT userid FROM tree)")))},
+			(*Query).prepareUpdateQuery,
+			"WITH RECURSIVE tree(userid, counter) AS (" + tree + "), big AS (SELECT orders.ownerid FROM orders WHERE orders.total > $1) UPDATE user SET (userid,counter) = ($2,$3) WHERE user.userid IN (SELECT userid FROM tree) AND user.userid = $4;",
+			[]interface{}{100, 3, 4, 3},
+		},
+		{
+			[]QueryOption{With("big", big), Where(IsIn("user.userid", Col("(SELECT ownerid FROM big)")))},
+			(*Query).prepareDeleteQuery,
+			"WITH big AS (SELECT orders.ownerid FROM orders WHERE orders.total > $1) DELETE FROM user WHERE user.userid IN (SELECT ownerid FROM big) AND user.userid = $2;",
+			[]interface{}{100, 3},
+		},
+	}
+
+	for _, tc := range cases {
+		q, err := NewQuery(&User{UserID: 3, Counter: 4}, tc.opts...)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if err := tc.prepare(q); err != nil {
+			t.Error(err)
+		}
+
+		if tc.want != q.SQL || !reflect.DeepEqual(tc.wantArgs, q.args) {
+			t.Errorf("Error: expected %s and %v, found %s and %v", tc.want, tc.wantArgs, q.SQL, q.args)
+		}
+	}
+
+	if _, err := With("", big)(&Query{}); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	if _, err := With("big", 1)(&Query{}); err == nil {
+		t.Error("Expected error found nil")
+	}
+}
+
+func mustNewQuery(t *testing.T, modelInterface interface{}, opts ...QueryOption) *Query {
+	q, err := NewQuery(modelInterface, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return q
+}
+
+func TestPrepareCompoundQuery(t *testing.T) {
+	q1 := mustNewQuery(t, &[]*User{}, Where(IsLessThan("userid", 3)))
+	q2 := mustNewQuery(t, &[]*User{}, Where(IsGreaterThan("userid", 8)))
+	q3 := mustNewQuery(t, &[]*User{}, Where(IsEqualsTo("counter", 1)))
+
+	cases := []struct {
+		opts     []QueryOption
+		prepare  func(q *Query) error
+		want     string
+		wantArgs []interface{}
+	}{
+		{
+			[]QueryOption{Union(q1, q2), OrderBy("userid DESC"), Limit(5)},
+			(*Query).prepareSelectQuery,
+			"(SELECT * FROM user WHERE userid < $1) UNION (SELECT * FROM user WHERE userid > $2) ORDER BY userid DESC LIMIT 5;",
+			[]interface{}{3, 8},
+		},
+		{
+			[]QueryOption{Except(mustNewQuery(t, &[]*User{}, UnionAll(q1, q2)), q3)},
+			(*Query).prepareSelectQuery,
+			"((SELECT * FROM user WHERE userid < $1) UNION ALL (SELECT * FROM user WHERE userid > $2)) EXCEPT (SELECT * FROM user WHERE counter = $3);",
+			[]interface{}{3, 8, 1},
+		},
+		{
+			[]QueryOption{Intersect(q1, q3), Limit(5)},
+			(*Query).prepareCountQuery,
+			"SELECT COUNT(*) FROM ((SELECT * FROM user WHERE userid < $1) INTERSECT (SELECT * FROM user WHERE counter = $2) LIMIT 5) AS count_query;",
+			[]interface{}{3, 1},
+		},
+		{
+			[]QueryOption{Union(q1, q2)},
+			func(q *Query) error { return q.prepareAggregateQuery("SUM", "counter") },
+			"SELECT SUM(counter) FROM ((SELECT * FROM user WHERE userid < $1) UNION (SELECT * FROM user WHERE userid > $2)) AS aggregate_query;",
+			[]interface{}{3, 8},
+		},
+	}
+
+	for _, tc := range cases {
+		q, err := NewQuery(&[]*User{}, tc.opts...)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if err := tc.prepare(q); err != nil {
+			t.Error(err)
+		}
+
+		if tc.want != q.SQL || !reflect.DeepEqual(tc.wantArgs, q.args) {
+			t.Errorf("Error: expected %s and %v, found %s and %v", tc.want, tc.wantArgs, q.SQL, q.args)
+		}
+	}
+}
+
+func TestCompoundQueryErrors(t *testing.T) {
+	q1 := mustNewQuery(t, &[]*User{})
+
+	if _, err := Union(q1)(&Query{}); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	if _, err := Union(q1, nil)(&Query{}); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	q := mustNewQuery(t, &[]*User{}, Union(q1, q1), Where(IsEqualsTo("userid", 1)))
+	if err := q.prepareSelectQuery(); err == nil {
+		t.Error("Expected error found nil")
+	}
+}
+
+func TestPrepareSelectLock(t *testing.T) {
+	cases := []struct {
+		opts []QueryOption
+		want string
+	}{
+		{
+			[]QueryOption{ForUpdate(), SkipLocked(), Limit(10)},
+			"SELECT * FROM user LIMIT 10 FOR UPDATE SKIP LOCKED;",
+		},
+		{
+			[]QueryOption{Alias("u"), ForShare("u", "o"), NoWait(), Limit(1), Offset(2)},
+			"SELECT * FROM user AS u LIMIT 1 OFFSET 2 FOR SHARE OF u, o NOWAIT;",
+		},
+	}
+
+	for _, tc := range cases {
+		q := mustNewQuery(t, &[]*User{}, tc.opts...)
+
+		if err := q.prepareSelectQuery(); err != nil {
+			t.Error(err)
+		}
+
+		if tc.want != q.SQL {
+			t.Errorf("Error: expected %s, found %s", tc.want, q.SQL)
+		}
+	}
+
+	q := mustNewQuery(t, &[]*User{}, SkipLocked())
+	if err := q.prepareSelectQuery(); err == nil {
+		t.Error("Expected error found nil")
+	}
+}
+
+func TestLockOutsideTransaction(t *testing.T) {
+	db := &DB{}
+
+	if err := db.Find(&[]*User{}, ForUpdate()); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	if _, err := db.Rows(&User{}, ForShare()); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	if _, err := db.Count(&User{}, ForUpdate()); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	if _, err := db.Exists(&User{}, ForUpdate()); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	if _, err := db.Sum(&User{}, "counter", ForUpdate()); err == nil {
+		t.Error("Expected error found nil")
+	}
+}
+
+func TestUnsupportedLockInTransaction(t *testing.T) {
+	db, d := newResultDB([]string{"exists"}, []driver.Value{true})
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	q1 := mustNewQuery(t, &[]*User{}, Where(IsLessThan("userid", 3)))
+	q2 := mustNewQuery(t, &[]*User{}, Where(IsGreaterThan("userid", 8)))
+	if err := tx.Find(&[]*User{}, Union(q1, q2), ForUpdate(), SkipLocked()); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	if _, err := tx.Count(&User{}, ForUpdate()); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	if _, err := tx.Count(&User{}, GroupBy("counter"), ForUpdate()); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	var max int
+	if err := tx.Max(&User{}, "counter", &max, ForUpdate()); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	if _, err := tx.Exists(&User{}, ForUpdate()); err != nil {
+		t.Error(err)
+	}
+
+	want := []string{"SELECT EXISTS(SELECT 1 FROM user LIMIT 1 FOR UPDATE);"}
+	if !reflect.DeepEqual(want, d.queries) {
+		t.Errorf("Error: expected %v, found %v", want, d.queries)
+	}
+}
+
+type ValidatedProfile struct {
+	UserID int    `fury:"primary_key"`
+	Email  string `fury:"max_len:5"`
+	Age    int    `fury:"min:0"`
+}
+
+func TestValidateBeforeWrite(t *testing.T) {
+	pool := &recordingConnectionPool{affected: 1}
+	db, err := ConnectMock(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profile := &ValidatedProfile{UserID: 1, Email: "some@test.com", Age: -1}
+	err = db.Insert(profile)
+	if validationErr, ok := err.(*model.ValidationError); !ok || len(validationErr.Errors) != 2 {
+		t.Errorf("Error: expected validation error of %d fields, found %v", 2, err)
+	}
+
+	if err := db.Update(profile, Columns("email")); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	profile.Email = "a@b.c"
+	if err := db.Update(profile, Columns("email")); err != nil {
+		t.Error(err)
+	}
+
+	err = db.UpdateMap(profile, map[string]interface{}{"email": strings.Repeat("x", 500), "age": -1})
+	if validationErr, ok := err.(*model.ValidationError); !ok || len(validationErr.Errors) != 2 {
+		t.Errorf("Error: expected validation error of %d fields, found %v", 2, err)
+	}
+
+	if _, err := db.UpdateWhere(profile, Set{"email": strings.Repeat("x", 500)}, AllowFullTable()); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	// SQL value is computed by database, so it is not validated
+	if err := db.UpdateMap(profile, map[string]interface{}{"email": "a@b.c", "age": Sub(Col("age"), 1)}); err != nil {
+		t.Error(err)
+	}
+
+	if len(pool.queries) != 2 {
+		t.Errorf("Error: expected %v query, found %v", 2, pool.queries)
+	}
+}
+
+type Timestamps struct {
+	CreatedAt time.Time  `fury:"created_at"`
+	UpdatedAt *time.Time `fury:"updated_at"`
+}
+
+type Address struct {
+	Street string
+	City   string
+}
+
+type Customer struct {
+	Timestamps
+	CustomerID int `fury:"primary_key"`
+	Name       string
+	Address    Address `fury:"embedded,prefix:addr_"`
+}
+
+func TestPrepareEmbeddedStruct(t *testing.T) {
+	now := time.Date(2019, 6, 28, 2, 26, 0, 0, time.UTC)
+	pool := &recordingConnectionPool{affected: 1}
+	db, err := ConnectMock(pool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetClock(func() time.Time { return now })
+
+	customer := &Customer{CustomerID: 1, Name: "Test", Address: Address{Street: "Main", City: "Bandung"}}
+	if err := db.Insert(customer); err != nil {
+		t.Error(err)
+	}
+
+	if !customer.CreatedAt.Equal(now) {
+		t.Errorf("Error: expected %v, found %v", now, customer.CreatedAt)
+	}
+
+	if err := db.Update(customer, Columns("addr_city")); err != nil {
+		t.Error(err)
+	}
+
+	want := []string{
+		"INSERT INTO customer(createdat,updatedat,customerid,name,addr_street,addr_city) VALUES($1,$2,$3,$4,$5,$6);",
+		"UPDATE customer SET (addr_city,updatedat) = ($1,$2) WHERE customer.customerid = $3;",
+	}
+	if !reflect.DeepEqual(want, pool.queries) {
+		t.Errorf("Error: expected %v, found %v", want, pool.queries)
+	}
+}
+
+type NullableProfile struct {
+	UserID    *int `fury:"primary_key"`
+	Nickname  sql.NullString
+	Avatar    []byte `fury:"omitempty"`
+	LastLogin *time.Time
+}
+
+func TestPrepareNullableFields(t *testing.T) {
+	userID := 1
+	profile := &NullableProfile{UserID: &userID}
+	q, err := NewQuery(profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := q.prepareUpdateQuery(); err != nil {
+		t.Error(err)
+	}
+
+	want := "UPDATE nullableprofile SET (userid,nickname,lastlogin) = ($1,$2,$3) WHERE nullableprofile.userid = $4;"
+	if q.SQL != want || len(q.args) != 4 || q.args[3] != 1 {
+		t.Errorf("Error: expected %s and %v, found %s and %v", want, 1, q.SQL, q.args)
+	}
+
+	// Nil pointer and invalid sql.NullString are written as NULL
+	for _, arg := range q.args[1:3] {
+		if val, err := driver.DefaultParameterConverter.ConvertValue(arg); err != nil || val != nil {
+			t.Errorf("Error: expected %v, found %v", nil, val)
+		}
+	}
+
+	q, err = NewQuery(&NullableProfile{Avatar: []byte{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := q.prepareDeleteQuery(); err == nil {
+		t.Error("Expected error found nil")
+	}
 }
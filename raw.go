@@ -0,0 +1,71 @@
+package fury
+
+import (
+	"database/sql"
+	"reflect"
+	"time"
+)
+
+// RawQuery struct to store hand-written SQL and its arguments
+// 	Use DB.Raw(sql, args...) to create new instance of this struct.
+type RawQuery struct {
+	db   *DB
+	sql  string
+	args []interface{}
+}
+
+// Raw method create raw query, use ? as placeholder for the arguments
+func (db *DB) Raw(sql string, args ...interface{}) *RawQuery {
+	return &RawQuery{
+		db:   db,
+		sql:  sql,
+		args: args,
+	}
+}
+
+// Scan method run the raw query and scan the result to dest
+//	Supported dest are the same as Find method, pointer to slice of scalar value is also supported for single column result
+func (rq *RawQuery) Scan(dest interface{}) error {
+	q, err := newRawQuery(dest)
+	if err != nil {
+		return err
+	}
+
+	q.SQL = rq.sql
+	q.args = rq.args
+	q.replaceSQLPlaceholder()
+
+	return rq.db.withQuery(q).executeScanQuery()
+}
+
+// Exec method run the raw query without returning any rows
+func (rq *RawQuery) Exec() (sql.Result, error) {
+	q := &Query{
+		SQL:  rq.sql,
+		args: rq.args,
+	}
+	q.replaceSQLPlaceholder()
+
+	return rq.db.Exec(q.SQL, q.args...)
+}
+
+// Create new query context for raw query destination
+func newRawQuery(dest interface{}) (*Query, error) {
+	reflectType := reflect.TypeOf(dest)
+	if reflectType != nil && reflectType.Kind() == reflect.Ptr && reflectType.Elem().Kind() == reflect.Slice {
+		elemType := reflectType.Elem().Elem()
+		if elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+
+		if (elemType.Kind() != reflect.Struct || elemType == reflect.TypeOf(time.Time{})) && elemType.Kind() != reflect.Map {
+			return &Query{
+				scanTo:      dest,
+				modelPtrCtr: -1,
+				destination: scalarDestination,
+			}, nil
+		}
+	}
+
+	return NewQuery(dest)
+}
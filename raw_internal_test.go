@@ -0,0 +1,48 @@
+package fury
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewRawQueryDestination(t *testing.T) {
+	cases := []struct {
+		have interface{}
+		want destinationType
+	}{
+		{&User{}, modelDestination},
+		{&[]*User{}, modelDestination},
+		{&[]map[string]interface{}{}, mapDestination},
+		{&[]string{}, scalarDestination},
+		{&[]*int{}, scalarDestination},
+		{&[]time.Time{}, scalarDestination},
+	}
+
+	for _, tc := range cases {
+		q, err := newRawQuery(tc.have)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if q.destination != tc.want {
+			t.Errorf("Error: expected %v, found %v", tc.want, q.destination)
+		}
+	}
+}
+
+func TestNewRawQueryInvalidDestination(t *testing.T) {
+	cases := []struct {
+		have interface{}
+	}{
+		{nil},
+		{User{}},
+		{new(int)},
+	}
+
+	for _, tc := range cases {
+		if _, err := newRawQuery(tc.have); err == nil {
+			t.Error("Expected error found nil")
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package fury
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/nandaryanizar/fury/model"
+)
+
+// Rows struct to iterate query result one row at a time
+// 	Use DB.Rows(model, opts...) to create new instance of this struct, and always call Close when done.
+type Rows struct {
+	db        *DB
+	rows      *sql.Rows
+	columns   []string
+	query     *Query
+	scanDest  interface{}
+	scanModel *model.Model
+}
+
+// Rows method run the query and return iterator over the result instead of scanning all records at once
+func (db *DB) Rows(modelInterface interface{}, opts ...QueryOption) (*Rows, error) {
+	newDB, err := db.cloneWithOptions(modelInterface, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := newDB.checkLockInTransaction(); err != nil {
+		return nil, err
+	}
+
+	if err := newDB.query.prepareSelectQuery(); err != nil {
+		return nil, err
+	}
+
+	rows, err := newDB.Query(newDB.query.SQL, newDB.query.args...)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		return nil, err
+	}
+
+	return &Rows{
+		db:      newDB,
+		rows:    rows,
+		columns: columns,
+		query:   newDB.query,
+	}, nil
+}
+
+// Next method prepare the next row to be scanned, return false if there is no more row
+func (r *Rows) Next() bool {
+	return r.rows.Next()
+}
+
+// Scan method scan current row to dest, dest must be pointer to struct
+//	Scanning to the same dest on every row reuses its model, so only the field values are replaced
+func (r *Rows) Scan(dest interface{}) error {
+	if dest != r.scanDest {
+		reflectVal := reflect.ValueOf(dest)
+		if reflectVal.Kind() != reflect.Ptr || reflectVal.Elem().Kind() != reflect.Struct {
+			return fmt.Errorf("Error: expected pointer to struct, found %v", reflectVal.Kind())
+		}
+
+		_, m, err := model.NewModels(dest)
+		if err != nil {
+			return err
+		}
+
+		if r.query.strictColumns {
+			if unknown := m.GetUnknownColumns(r.columns); len(unknown) > 0 {
+				return fmt.Errorf("Error: columns %v have no matching field in %s", unknown, m.Name)
+			}
+		}
+
+		r.scanDest = dest
+		r.scanModel = m
+	}
+
+	if err := r.rows.Scan(r.scanModel.GetScanPtrByColumnNames(r.columns)...); err != nil {
+		return err
+	}
+
+	r.scanModel.FinishScan()
+	r.scanModel.TakeSnapshot()
+	return r.db.callHook(r.scanModel, afterFindHook)
+}
+
+// Err method return error encountered during iteration
+func (r *Rows) Err() error {
+	return r.rows.Err()
+}
+
+// Close method close the rows, Next will return false after this method is called
+func (r *Rows) Close() error {
+	return r.rows.Close()
+}
+
+// FindEach method scan every queried record to model and call the callback after each row
+//	Callback must be function with model type as its only parameter and return error, e.g. func(*Account) error.
+//	The same model instance is reused for every row, returning error from callback stop the iteration.
+func (db *DB) FindEach(modelInterface interface{}, callback interface{}, opts ...QueryOption) error {
+	fn := reflect.ValueOf(callback)
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+	if fn.Kind() != reflect.Func || fn.Type().NumIn() != 1 || fn.Type().NumOut() != 1 ||
+		fn.Type().In(0) != reflect.TypeOf(modelInterface) || fn.Type().Out(0) != errorType {
+		return errors.New("Error: callback must be function with the model type as parameter and error as return value")
+	}
+
+	rows, err := db.Rows(modelInterface, opts...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	modelVal := reflect.ValueOf(modelInterface)
+	for rows.Next() {
+		if err := rows.Scan(modelInterface); err != nil {
+			return err
+		}
+
+		ret := fn.Call([]reflect.Value{modelVal})
+		if err, ok := ret[0].Interface().(error); ok && err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
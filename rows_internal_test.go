@@ -0,0 +1,24 @@
+package fury
+
+import (
+	"testing"
+)
+
+func TestFindEachInvalidCallback(t *testing.T) {
+	cases := []struct {
+		have interface{}
+	}{
+		{nil},
+		{func() error { return nil }},
+		{func(u User) error { return nil }},
+		{func(u *User) {}},
+		{func(u *User) bool { return true }},
+	}
+
+	db := &DB{}
+	for _, tc := range cases {
+		if err := db.FindEach(&User{}, tc.have); err == nil {
+			t.Error("Expected error found nil")
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package fury
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Beginner interface
+// 	Connection pool that implements this interface can start transaction, e.g. *sql.DB
+type Beginner interface {
+	Begin() (*sql.Tx, error)
+}
+
+// txConnection wrap transaction so it can be used as connection pool of DB
+type txConnection struct {
+	*sql.Tx
+}
+
+// Close method return error, transaction must be ended with Commit or Rollback instead
+func (tc *txConnection) Close() error {
+	return errors.New("Error: cannot close connection pool inside transaction, use Commit or Rollback")
+}
+
+// Ping method do nothing as the transaction connection is already established
+func (tc *txConnection) Ping() error {
+	return nil
+}
+
+// Begin method start transaction and return DB which run every query inside the transaction
+// 	End the transaction with Commit or Rollback method of the returned DB.
+func (db *DB) Begin() (*DB, error) {
+	if db.tx != nil {
+		return nil, errors.New("Error: transaction already started")
+	}
+
+	beginner, ok := db.ConnectionPooler.(Beginner)
+	if !ok {
+		return nil, fmt.Errorf("Error: connection pool %T does not support transaction", db.ConnectionPooler)
+	}
+
+	tx, err := beginner.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	return &DB{
+		ConnectionPooler: &txConnection{tx},
+		config:           db.config,
+		tx:               tx,
+	}, nil
+}
+
+// Commit method commit the transaction started with Begin method
+func (db *DB) Commit() error {
+	if db.tx == nil {
+		return errors.New("Error: commit outside transaction")
+	}
+
+	return db.tx.Commit()
+}
+
+// Rollback method abort the transaction started with Begin method
+func (db *DB) Rollback() error {
+	if db.tx == nil {
+		return errors.New("Error: rollback outside transaction")
+	}
+
+	return db.tx.Rollback()
+}
+
+// Transaction method run fn inside transaction, the transaction is committed if fn return nil and rolled back otherwise
+func (db *DB) Transaction(fn func(tx *DB) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// InTransaction method return true if the DB run query inside transaction
+func (db *DB) InTransaction() bool {
+	return db.tx != nil
+}
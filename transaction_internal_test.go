@@ -0,0 +1,49 @@
+package fury
+
+import (
+	"database/sql"
+	"testing"
+)
+
+type mockConnectionPool struct{}
+
+func (mcp *mockConnectionPool) Close() error { return nil }
+
+func (mcp *mockConnectionPool) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return nil, nil
+}
+
+func (mcp *mockConnectionPool) Ping() error { return nil }
+
+func (mcp *mockConnectionPool) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, nil
+}
+
+func (mcp *mockConnectionPool) QueryRow(query string, args ...interface{}) *sql.Row { return nil }
+
+func TestTransactionUnsupported(t *testing.T) {
+	db, err := ConnectMock(&mockConnectionPool{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Begin(); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	if err := db.Transaction(func(tx *DB) error { return nil }); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	if err := db.Commit(); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	if err := db.Rollback(); err == nil {
+		t.Error("Expected error found nil")
+	}
+
+	if db.InTransaction() {
+		t.Error("Error: DB should not be in transaction")
+	}
+}